@@ -1,7 +1,9 @@
 package config
 
 import (
+	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -22,6 +24,16 @@ type Config struct {
 	R2SecretKey string
 	R2Bucket    string
 	R2PublicURL string
+
+	// No-show worker
+	NoShowGracePeriod   time.Duration
+	NoShowCheckInterval time.Duration
+
+	// Package start job
+	PackageCheckInterval time.Duration
+
+	// Goal deadline job
+	GoalCheckInterval time.Duration
 }
 
 // Load loads configuration from environment variables
@@ -42,6 +54,13 @@ func Load() *Config {
 		R2SecretKey: getEnv("R2_SECRET_ACCESS_KEY", ""),
 		R2Bucket:    getEnv("R2_BUCKET_NAME", "ptmate-photos"),
 		R2PublicURL: getEnv("R2_PUBLIC_URL", ""),
+
+		NoShowGracePeriod:   getEnvDuration("NO_SHOW_GRACE_PERIOD", 2*time.Hour),
+		NoShowCheckInterval: getEnvDuration("NO_SHOW_CHECK_INTERVAL", 10*time.Minute),
+
+		PackageCheckInterval: getEnvDuration("PACKAGE_CHECK_INTERVAL", 10*time.Minute),
+
+		GoalCheckInterval: getEnvDuration("GOAL_CHECK_INTERVAL", 10*time.Minute),
 	}
 }
 
@@ -52,3 +71,18 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration parses a duration such as "90m" from an environment variable
+// or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Printf("Warning: invalid %s %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return d
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"fmt"
+
+	"ptmate/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedExercise describes an exercise of the global library
+type seedExercise struct {
+	key       string
+	name      string
+	nameTR    string // empty where the English name is used in Turkish too
+	equipment string
+	pattern   string
+	primary   []string
+	secondary []string
+}
+
+// globalExercises is the exercise library shipped to every trainer
+var globalExercises = []seedExercise{
+	// Lower body
+	{"back_squat", "Barbell Back Squat", "Halterle Arka Squat", "barbell", "squat", []string{"quads", "glutes"}, []string{"hamstrings", "adductors", "lower_back"}},
+	{"front_squat", "Barbell Front Squat", "Halterle Ön Squat", "barbell", "squat", []string{"quads"}, []string{"glutes", "abs", "upper_back"}},
+	{"goblet_squat", "Goblet Squat", "Kadeh Squat", "kettlebell", "squat", []string{"quads", "glutes"}, []string{"abs"}},
+	{"leg_press", "Leg Press", "Bacak Presi", "machine", "squat", []string{"quads", "glutes"}, []string{"hamstrings"}},
+	{"deadlift", "Barbell Deadlift", "Halterle Ölü Kaldırış", "barbell", "hinge", []string{"hamstrings", "glutes", "lower_back"}, []string{"traps", "forearms", "quads"}},
+	{"romanian_deadlift", "Romanian Deadlift", "Romen Ölü Kaldırış", "barbell", "hinge", []string{"hamstrings", "glutes"}, []string{"lower_back", "forearms"}},
+	{"trap_bar_deadlift", "Trap Bar Deadlift", "Altıgen Barla Ölü Kaldırış", "trap_bar", "hinge", []string{"quads", "glutes", "hamstrings"}, []string{"lower_back", "traps"}},
+	{"hip_thrust", "Barbell Hip Thrust", "Halterle Kalça İtişi", "barbell", "hinge", []string{"glutes"}, []string{"hamstrings"}},
+	{"kettlebell_swing", "Kettlebell Swing", "Kettlebell Salınımı", "kettlebell", "hinge", []string{"glutes", "hamstrings"}, []string{"lower_back", "shoulders"}},
+	{"walking_lunge", "Walking Lunge", "Yürüyerek Hamle", "dumbbell", "lunge", []string{"quads", "glutes"}, []string{"hamstrings", "adductors"}},
+	{"bulgarian_split_squat", "Bulgarian Split Squat", "Bulgar Bölünmüş Squat", "dumbbell", "lunge", []string{"quads", "glutes"}, []string{"adductors"}},
+	{"step_up", "Step-Up", "Basamağa Çıkış", "dumbbell", "lunge", []string{"quads", "glutes"}, []string{"calves"}},
+	{"leg_extension", "Leg Extension", "Bacak Ekstansiyonu", "machine", "isolation", []string{"quads"}, nil},
+	{"leg_curl", "Lying Leg Curl", "Yüzüstü Bacak Bükme", "machine", "isolation", []string{"hamstrings"}, []string{"calves"}},
+	{"standing_calf_raise", "Standing Calf Raise", "Ayakta Baldır Kaldırma", "machine", "isolation", []string{"calves"}, nil},
+	{"hip_abduction", "Hip Abduction", "Kalça Abdüksiyonu", "machine", "isolation", []string{"abductors"}, []string{"glutes"}},
+	{"hip_adduction", "Hip Adduction", "Kalça Addüksiyonu", "machine", "isolation", []string{"adductors"}, nil},
+
+	// Upper body push
+	{"bench_press", "Barbell Bench Press", "Halterle Göğüs Presi", "barbell", "horizontal_push", []string{"chest"}, []string{"triceps", "shoulders"}},
+	{"incline_dumbbell_press", "Incline Dumbbell Press", "Eğimli Dumbbell Göğüs Presi", "dumbbell", "horizontal_push", []string{"chest", "shoulders"}, []string{"triceps"}},
+	{"push_up", "Push-Up", "Şınav", "bodyweight", "horizontal_push", []string{"chest"}, []string{"triceps", "shoulders", "abs"}},
+	{"dip", "Parallel Bar Dip", "Paralel Barda Dips", "bodyweight", "vertical_push", []string{"chest", "triceps"}, []string{"shoulders"}},
+	{"overhead_press", "Barbell Overhead Press", "Halterle Omuz Presi", "barbell", "vertical_push", []string{"shoulders"}, []string{"triceps", "upper_back"}},
+	{"dumbbell_shoulder_press", "Seated Dumbbell Shoulder Press", "Oturarak Dumbbell Omuz Presi", "dumbbell", "vertical_push", []string{"shoulders"}, []string{"triceps"}},
+	{"cable_fly", "Cable Fly", "Kabloda Göğüs Açış", "cable", "isolation", []string{"chest"}, []string{"shoulders"}},
+	{"lateral_raise", "Dumbbell Lateral Raise", "Dumbbell ile Yana Açış", "dumbbell", "isolation", []string{"shoulders"}, []string{"traps"}},
+	{"triceps_pushdown", "Cable Triceps Pushdown", "Kabloda Arka Kol İtişi", "cable", "isolation", []string{"triceps"}, nil},
+	{"skull_crusher", "EZ-Bar Skull Crusher", "EZ Barla Yatarak Arka Kol Açış", "barbell", "isolation", []string{"triceps"}, nil},
+
+	// Upper body pull
+	{"pull_up", "Pull-Up", "Barfiks", "pull_up_bar", "vertical_pull", []string{"lats"}, []string{"biceps", "upper_back", "forearms"}},
+	{"chin_up", "Chin-Up", "Ters Tutuş Barfiks", "pull_up_bar", "vertical_pull", []string{"lats", "biceps"}, []string{"upper_back"}},
+	{"lat_pulldown", "Lat Pulldown", "Önden Sırt Çekişi", "cable", "vertical_pull", []string{"lats"}, []string{"biceps", "upper_back"}},
+	{"barbell_row", "Barbell Bent-Over Row", "Halterle Eğilerek Kürek Çekiş", "barbell", "horizontal_pull", []string{"upper_back", "lats"}, []string{"biceps", "lower_back", "forearms"}},
+	{"dumbbell_row", "One-Arm Dumbbell Row", "Tek Kol Dumbbell Kürek Çekiş", "dumbbell", "horizontal_pull", []string{"lats", "upper_back"}, []string{"biceps"}},
+	{"seated_cable_row", "Seated Cable Row", "Oturarak Kabloda Kürek Çekiş", "cable", "horizontal_pull", []string{"upper_back", "lats"}, []string{"biceps"}},
+	{"face_pull", "Face Pull", "Kabloda Yüze Çekiş", "cable", "horizontal_pull", []string{"shoulders", "upper_back"}, []string{"traps"}},
+	{"inverted_row", "Inverted Row", "Ters Kürek Çekiş", "bodyweight", "horizontal_pull", []string{"upper_back", "lats"}, []string{"biceps", "abs"}},
+	{"barbell_curl", "Barbell Curl", "Halterle Pazı Bükme", "barbell", "isolation", []string{"biceps"}, []string{"forearms"}},
+	{"hammer_curl", "Dumbbell Hammer Curl", "Dumbbell ile Çekiç Bükme", "dumbbell", "isolation", []string{"biceps", "forearms"}, nil},
+	{"shrug", "Dumbbell Shrug", "Dumbbell ile Omuz Silkme", "dumbbell", "isolation", []string{"traps"}, []string{"forearms"}},
+
+	// Core and carries
+	{"plank", "Plank", "", "bodyweight", "anti_rotation", []string{"abs"}, []string{"obliques", "shoulders"}},
+	{"side_plank", "Side Plank", "Yan Plank", "bodyweight", "anti_rotation", []string{"obliques"}, []string{"abs", "abductors"}},
+	{"pallof_press", "Pallof Press", "", "cable", "anti_rotation", []string{"obliques", "abs"}, nil},
+	{"dead_bug", "Dead Bug", "Ölü Böcek", "bodyweight", "anti_rotation", []string{"abs"}, nil},
+	{"hanging_leg_raise", "Hanging Leg Raise", "Asılı Bacak Kaldırma", "pull_up_bar", "isolation", []string{"abs"}, []string{"obliques", "forearms"}},
+	{"cable_woodchop", "Cable Woodchop", "Kablo Oduncu", "cable", "rotation", []string{"obliques"}, []string{"abs", "shoulders"}},
+	{"back_extension", "Back Extension", "Sırt Ekstansiyonu", "bench", "hinge", []string{"lower_back"}, []string{"glutes", "hamstrings"}},
+	{"farmers_carry", "Farmer's Carry", "Çiftçi Yürüyüşü", "dumbbell", "carry", []string{"forearms", "traps"}, []string{"abs", "full_body"}},
+
+	// Conditioning
+	{"burpee", "Burpee", "", "bodyweight", "conditioning", []string{"full_body"}, nil},
+	{"medicine_ball_slam", "Medicine Ball Slam", "Sağlık Topu Yere Vurma", "medicine_ball", "conditioning", []string{"full_body"}, []string{"abs", "shoulders"}},
+	{"mountain_climber", "Mountain Climber", "Dağcı", "bodyweight", "conditioning", []string{"abs"}, []string{"shoulders", "quads"}},
+	{"band_pull_apart", "Band Pull-Apart", "Bantla Yana Açış", "band", "mobility", []string{"upper_back", "shoulders"}, nil},
+}
+
+// SeedExercises adds the global exercise library. Exercises already in the
+// database (by key) only get the shipped translations, so it is safe to run on
+// every start and to run from several replicas at once.
+func SeedExercises(db *gorm.DB) error {
+	exercises := make([]models.Exercise, 0, len(globalExercises))
+	for _, e := range globalExercises {
+		translations := map[string]models.ExerciseTranslation{
+			models.LanguageEnglish: {Name: e.name},
+		}
+		// Without a Turkish name clients fall back to English
+		if e.nameTR != "" {
+			translations[models.LanguageTurkish] = models.ExerciseTranslation{Name: e.nameTR}
+		}
+		exercises = append(exercises, models.Exercise{
+			Key:              e.key,
+			Name:             e.name,
+			PrimaryMuscles:   e.primary,
+			SecondaryMuscles: append([]string{}, e.secondary...),
+			Equipment:        e.equipment,
+			MovementPattern:  e.pattern,
+			Translations:     translations,
+		})
+	}
+
+	if err := db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "key"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "trainer_id IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"translations"}),
+	}).Create(&exercises).Error; err != nil {
+		return fmt.Errorf("failed to seed exercise library: %w", err)
+	}
+	return nil
+}
+
+// DedupeExercises removes global exercises seeded more than once under the same
+// key before the unique index on it is created. References to a duplicate are
+// moved to the oldest copy, which is kept.
+func DedupeExercises(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Exercise{}) {
+		return nil
+	}
+
+	duplicates := `SELECT id, FIRST_VALUE(id) OVER (PARTITION BY key ORDER BY created_at, id) AS keep
+		FROM exercises WHERE trainer_id IS NULL`
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"program_exercises", "workout_exercises", "strength_records"} {
+			if !tx.Migrator().HasTable(table) {
+				continue
+			}
+			if err := tx.Exec(`UPDATE ` + table + ` SET exercise_id = d.keep FROM (` + duplicates + `) d
+				WHERE ` + table + `.exercise_id = d.id AND d.id <> d.keep`).Error; err != nil {
+				return fmt.Errorf("failed to move %s to deduplicated exercises: %w", table, err)
+			}
+		}
+		if err := tx.Exec(`DELETE FROM exercises USING (` + duplicates + `) d
+			WHERE exercises.id = d.id AND d.id <> d.keep`).Error; err != nil {
+			return fmt.Errorf("failed to remove duplicate exercises: %w", err)
+		}
+		return nil
+	})
+}
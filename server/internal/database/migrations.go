@@ -0,0 +1,69 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MigratePackages moves clients from the single TotalPackageSize field to the
+// packages table. Each client with a package size and no packages yet gets an
+// initial package, and their used sessions are assigned to it oldest first.
+// Clients that already have packages are skipped, so it is safe to run on every start.
+func MigratePackages(db *gorm.DB) error {
+	var clients []models.Client
+	if err := db.Where("total_package_size > 0").
+		Where("NOT EXISTS (SELECT 1 FROM packages WHERE packages.client_id = clients.id)").
+		Find(&clients).Error; err != nil {
+		return fmt.Errorf("failed to find clients to migrate: %w", err)
+	}
+
+	for _, client := range clients {
+		startsAt := client.CreatedAt
+		if client.PackageStartDate != nil {
+			startsAt = *client.PackageStartDate
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			pkg := models.Package{
+				ClientID:     client.ID,
+				SessionCount: client.TotalPackageSize,
+				Currency:     models.DefaultCurrency,
+				PurchasedAt:  startsAt,
+				StartsAt:     startsAt,
+				Status:       models.PackageStatusActive,
+				Notes:        "Migrated from the client's package size",
+			}
+			if err := tx.Create(&pkg).Error; err != nil {
+				return err
+			}
+
+			var sessionIDs []uuid.UUID
+			if err := tx.Model(&models.Session{}).
+				Where("client_id = ? AND package_id IS NULL AND status IN ?", client.ID, models.UsedStatuses()).
+				Order("scheduled_at ASC").
+				Limit(pkg.SessionCount).
+				Pluck("id", &sessionIDs).Error; err != nil {
+				return err
+			}
+			if len(sessionIDs) == 0 {
+				return nil
+			}
+
+			return tx.Model(&models.Session{}).Where("id IN ?", sessionIDs).UpdateColumn("package_id", pkg.ID).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate package for client %s: %w", client.ID, err)
+		}
+	}
+
+	if len(clients) > 0 {
+		log.Printf("Migrated %d clients to packages", len(clients))
+	}
+
+	return nil
+}
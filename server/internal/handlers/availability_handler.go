@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxSlotSearchDays limits how far a single free-slot search may reach
+const maxSlotSearchDays = 62
+
+// AvailabilityHandler handles working hours, time off and free-slot HTTP requests
+type AvailabilityHandler struct {
+	db *gorm.DB
+}
+
+// NewAvailabilityHandler creates a new AvailabilityHandler
+func NewAvailabilityHandler(db *gorm.DB) *AvailabilityHandler {
+	return &AvailabilityHandler{db: db}
+}
+
+// GetWorkingHours returns the trainer's weekly working-hour template
+func (h *AvailabilityHandler) GetWorkingHours(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	hours := make([]models.WorkingHours, 0)
+	if err := h.db.Where("trainer_id = ?", trainerID).
+		Order("weekday ASC, start_time ASC").
+		Find(&hours).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch working hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// SetWorkingHours replaces the trainer's weekly working-hour template
+func (h *AvailabilityHandler) SetWorkingHours(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.SetWorkingHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hours := make([]models.WorkingHours, 0, len(req.Hours))
+	for _, entry := range req.Hours {
+		if _, err := services.ClockRange(time.Now(), entry.StartTime, entry.EndTime); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		hours = append(hours, models.WorkingHours{
+			TrainerID: trainerID,
+			Weekday:   entry.Weekday,
+			StartTime: entry.StartTime,
+			EndTime:   entry.EndTime,
+		})
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trainer_id = ?", trainerID).Delete(&models.WorkingHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save working hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// GetOverrides returns date-specific availability overrides
+func (h *AvailabilityHandler) GetOverrides(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	query := h.db.Where("trainer_id = ?", trainerID)
+	if from := c.Query("from"); from != "" {
+		query = query.Where("date >= ?", from)
+	}
+	if to := c.Query("to"); to != "" {
+		query = query.Where("date <= ?", to)
+	}
+
+	overrides := make([]models.AvailabilityOverride, 0)
+	if err := query.Order("date ASC, start_time ASC").Find(&overrides).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch overrides"})
+		return
+	}
+
+	c.JSON(http.StatusOK, overrides)
+}
+
+// CreateOverride adds a date-specific availability override
+func (h *AvailabilityHandler) CreateOverride(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.CreateAvailabilityOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	day, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date, expected YYYY-MM-DD"})
+		return
+	}
+	if !req.IsUnavailable {
+		if _, err := services.ClockRange(day, req.StartTime, req.EndTime); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	override := models.AvailabilityOverride{
+		TrainerID:     trainerID,
+		Date:          req.Date,
+		StartTime:     req.StartTime,
+		EndTime:       req.EndTime,
+		IsUnavailable: req.IsUnavailable,
+		Note:          req.Note,
+	}
+	if override.IsUnavailable {
+		override.StartTime, override.EndTime = "", ""
+	}
+
+	if err := h.db.Create(&override).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create override"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, override)
+}
+
+// DeleteOverride removes a date-specific availability override
+func (h *AvailabilityHandler) DeleteOverride(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid override ID"})
+		return
+	}
+
+	result := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).Delete(&models.AvailabilityOverride{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete override"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Override not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Override deleted successfully"})
+}
+
+// GetTimeOff returns the trainer's time-off blocks
+func (h *AvailabilityHandler) GetTimeOff(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	query := h.db.Where("trainer_id = ?", trainerID)
+	if from := c.Query("from"); from != "" {
+		query = query.Where("ends_at >= ?", from)
+	}
+	if to := c.Query("to"); to != "" {
+		query = query.Where("starts_at <= ?", to)
+	}
+
+	timeOff := make([]models.TimeOff, 0)
+	if err := query.Order("starts_at ASC").Find(&timeOff).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time off"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeOff)
+}
+
+// CreateTimeOff adds a vacation or time-off block
+func (h *AvailabilityHandler) CreateTimeOff(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.CreateTimeOffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+
+	timeOff := models.TimeOff{
+		TrainerID: trainerID,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		Reason:    req.Reason,
+	}
+
+	if err := h.db.Create(&timeOff).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create time off"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, timeOff)
+}
+
+// DeleteTimeOff soft deletes a time-off block
+func (h *AvailabilityHandler) DeleteTimeOff(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time off ID"})
+		return
+	}
+
+	result := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).Delete(&models.TimeOff{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete time off"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Time off not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Time off deleted successfully"})
+}
+
+// GetSlots returns free slots of a given duration over a date range
+func (h *AvailabilityHandler) GetSlots(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
+	query, err := parseSlotQuery(c, trainer.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slots, err := query.find(h.db, trainer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate free slots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AvailableSlotsResponse{
+		Timezone:        trainer.Location().String(),
+		DurationMinutes: query.DurationMinutes,
+		Slots:           slots,
+	})
+}
+
+// slotQuery holds the parsed parameters of a free-slot search
+type slotQuery struct {
+	From            time.Time
+	To              time.Time
+	DurationMinutes int
+	StepMinutes     int
+}
+
+// parseSlotQuery reads from, to (YYYY-MM-DD, inclusive, default next 7 days),
+// duration (minutes, default 60) and step (minutes, default duration)
+func parseSlotQuery(c *gin.Context, loc *time.Location) (slotQuery, error) {
+	var q slotQuery
+
+	now := time.Now().In(loc)
+	q.From = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if from := c.Query("from"); from != "" {
+		parsed, err := parseRangeDate(from, loc, false)
+		if err != nil {
+			return q, fmt.Errorf("Invalid from date, expected YYYY-MM-DD")
+		}
+		q.From = parsed
+	}
+
+	q.To = q.From.AddDate(0, 0, 7)
+	if to := c.Query("to"); to != "" {
+		parsed, err := parseRangeDate(to, loc, true)
+		if err != nil {
+			return q, fmt.Errorf("Invalid to date, expected YYYY-MM-DD")
+		}
+		q.To = parsed
+	}
+
+	return q, q.parseOptions(c)
+}
+
+// parseRangeDate reads a YYYY-MM-DD date in loc. Range end dates are inclusive,
+// so for the end of a range it returns the start of the following day.
+func parseRangeDate(value string, loc *time.Location, end bool) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// parseOptions checks the query's range and reads duration (minutes, default 60)
+// and step (minutes, default duration)
+func (q *slotQuery) parseOptions(c *gin.Context) error {
+	if !q.To.After(q.From) {
+		return fmt.Errorf("to must not be before from")
+	}
+	if q.To.Sub(q.From) > maxSlotSearchDays*24*time.Hour {
+		return fmt.Errorf("Date range cannot exceed %d days", maxSlotSearchDays)
+	}
+
+	var err error
+	q.DurationMinutes, err = strconv.Atoi(c.DefaultQuery("duration", "60"))
+	if err != nil || q.DurationMinutes <= 0 {
+		return fmt.Errorf("Invalid duration")
+	}
+	q.StepMinutes, err = strconv.Atoi(c.DefaultQuery("step", strconv.Itoa(q.DurationMinutes)))
+	if err != nil || q.StepMinutes <= 0 {
+		return fmt.Errorf("Invalid step")
+	}
+
+	return nil
+}
+
+// find computes the free slots for the query, skipping slots that already started.
+// Slots are laid out from the start of each free window, so the ones left today
+// keep the same grid as on any other day.
+func (q slotQuery) find(db *gorm.DB, trainer models.Trainer) ([]models.TimeSlot, error) {
+	now := time.Now()
+	if !q.To.After(now) {
+		return []models.TimeSlot{}, nil
+	}
+
+	slots, err := freeSlots(db, trainer, q.From, q.To, time.Duration(q.DurationMinutes)*time.Minute, time.Duration(q.StepMinutes)*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := trainer.Location()
+	upcoming := make([]models.TimeSlot, 0, len(slots))
+	for _, s := range slots {
+		if s.Start.Before(now) {
+			continue
+		}
+		upcoming = append(upcoming, models.TimeSlot{Start: s.Start.In(loc), End: s.End.In(loc)})
+	}
+	return upcoming, nil
+}
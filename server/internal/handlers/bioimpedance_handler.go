@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BioimpedanceHandler handles bioimpedance scale reading HTTP requests
+type BioimpedanceHandler struct {
+	db *gorm.DB
+}
+
+// NewBioimpedanceHandler creates a new BioimpedanceHandler
+func NewBioimpedanceHandler(db *gorm.DB) *BioimpedanceHandler {
+	return &BioimpedanceHandler{db: db}
+}
+
+// GetByClient returns a client's bioimpedance readings, newest first
+func (h *BioimpedanceHandler) GetByClient(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var readings []models.Bioimpedance
+	if err := h.db.Where("client_id = ?", client.ID).Order("measured_at DESC").Find(&readings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bioimpedance readings"})
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, client.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+	for i := range readings {
+		readings[i].Present(system)
+	}
+
+	c.JSON(http.StatusOK, readings)
+}
+
+// Create records a bioimpedance reading
+func (h *BioimpedanceHandler) Create(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveBioimpedanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, client.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+
+	reading := models.Bioimpedance{ClientID: client.ID}
+	if problem := applyBioimpedance(&reading, req, system); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	// Default to current time if not provided
+	if reading.MeasuredAt.IsZero() {
+		reading.MeasuredAt = time.Now()
+	}
+
+	if err := h.db.Create(&reading).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bioimpedance reading"})
+		return
+	}
+	reading.Present(system)
+
+	c.JSON(http.StatusCreated, reading)
+}
+
+// GetByID returns a bioimpedance reading
+func (h *BioimpedanceHandler) GetByID(c *gin.Context) {
+	reading, ok := h.findReading(c)
+	if !ok {
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, reading.ClientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+	reading.Present(system)
+
+	c.JSON(http.StatusOK, reading)
+}
+
+// Update replaces a bioimpedance reading
+func (h *BioimpedanceHandler) Update(c *gin.Context) {
+	reading, ok := h.findReading(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveBioimpedanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, reading.ClientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+
+	measuredAt := reading.MeasuredAt
+	if problem := applyBioimpedance(&reading, req, system); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+	if reading.MeasuredAt.IsZero() {
+		reading.MeasuredAt = measuredAt
+	}
+
+	if err := h.db.Save(&reading).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bioimpedance reading"})
+		return
+	}
+	reading.Present(system)
+
+	c.JSON(http.StatusOK, reading)
+}
+
+// Delete soft deletes a bioimpedance reading
+func (h *BioimpedanceHandler) Delete(c *gin.Context) {
+	reading, ok := h.findReading(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(&reading).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete bioimpedance reading"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bioimpedance reading deleted successfully"})
+}
+
+// findClient loads the client in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *BioimpedanceHandler) findClient(c *gin.Context) (models.Client, bool) {
+	var client models.Client
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return client, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return client, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return client, false
+	}
+
+	return client, true
+}
+
+// findReading loads the bioimpedance reading in the URL if its client belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *BioimpedanceHandler) findReading(c *gin.Context) (models.Bioimpedance, bool) {
+	var reading models.Bioimpedance
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return reading, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bioimpedance reading ID"})
+		return reading, false
+	}
+
+	if err := h.db.Joins("JOIN clients ON clients.id = bioimpedance_records.client_id").
+		Where("bioimpedance_records.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&reading).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bioimpedance reading not found"})
+		return reading, false
+	}
+
+	return reading, true
+}
+
+// applyBioimpedance copies the request onto the reading, reading its values in the
+// request's unit system or else the client's. It returns a description of the
+// problem if a value is invalid.
+func applyBioimpedance(reading *models.Bioimpedance, req models.SaveBioimpedanceRequest, system models.UnitSystem) string {
+	reading.Device = req.Device
+	reading.WeightKg = req.WeightKg
+	reading.BodyFatPercent = req.BodyFatPercent
+	reading.SkeletalMuscleMassKg = req.SkeletalMuscleMassKg
+	reading.VisceralFatLevel = req.VisceralFatLevel
+	reading.BMRKcal = req.BMRKcal
+	reading.TotalBodyWaterL = req.TotalBodyWaterL
+	reading.Notes = req.Notes
+	reading.MeasuredAt = req.MeasuredAt
+	return reading.ApplyValues(orUnitSystem(req.UnitSystem, system), req.Values)
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookingHandler handles the public, token-scoped self-booking HTTP requests
+type BookingHandler struct {
+	db *gorm.DB
+}
+
+// NewBookingHandler creates a new BookingHandler
+func NewBookingHandler(db *gorm.DB) *BookingHandler {
+	return &BookingHandler{db: db}
+}
+
+// getBookingTrainer loads the trainer for the token in the URL.
+// It writes a 404 and returns false if the token is unknown or booking is disabled.
+func (h *BookingHandler) getBookingTrainer(c *gin.Context) (models.Trainer, bool) {
+	var trainer models.Trainer
+	if err := h.db.Where("booking_token = ? AND booking_enabled = ?", c.Param("token"), true).
+		First(&trainer).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking page not found"})
+		return trainer, false
+	}
+	return trainer, true
+}
+
+// GetProfile returns the trainer's public booking profile and rules
+func (h *BookingHandler) GetProfile(c *gin.Context) {
+	trainer, ok := h.getBookingTrainer(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PublicTrainerProfile{
+		FirstName:              trainer.FirstName,
+		LastName:               trainer.LastName,
+		Timezone:               trainer.Location().String(),
+		BookingDurationMinutes: trainer.BookingDurationMinutes,
+		BookingMinNoticeHours:  trainer.BookingMinNoticeHours,
+		BookingMaxAdvanceDays:  trainer.BookingMaxAdvanceDays,
+	})
+}
+
+// GetSlots returns bookable slots within the trainer's booking window
+func (h *BookingHandler) GetSlots(c *gin.Context) {
+	trainer, ok := h.getBookingTrainer(c)
+	if !ok {
+		return
+	}
+
+	query, err := parseSlotQuery(c, trainer.Location())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Clients always book the trainer's configured session length
+	query.DurationMinutes = trainer.BookingDurationMinutes
+	if c.Query("step") == "" {
+		query.StepMinutes = query.DurationMinutes
+	}
+
+	slots, err := query.find(h.db, trainer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate free slots"})
+		return
+	}
+
+	earliest, latest := trainer.BookingWindow(time.Now())
+	bookable := make([]models.TimeSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Start.Before(earliest) || slot.Start.After(latest) {
+			continue
+		}
+		bookable = append(bookable, slot)
+	}
+
+	c.JSON(http.StatusOK, models.AvailableSlotsResponse{
+		Timezone:        trainer.Location().String(),
+		DurationMinutes: trainer.BookingDurationMinutes,
+		Slots:           bookable,
+	})
+}
+
+// CreateRequest lets a known client request a slot; the session starts as pending
+func (h *BookingHandler) CreateRequest(c *gin.Context) {
+	trainer, ok := h.getBookingTrainer(c)
+	if !ok {
+		return
+	}
+
+	var req models.BookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Identify the client without revealing whether the email exists
+	var client models.Client
+	if err := h.db.Where("trainer_id = ? AND LOWER(email) = LOWER(?)", trainer.ID, strings.TrimSpace(req.Email)).
+		First(&client).Error; err != nil || !phoneMatches(client.Phone, req.Phone) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "We could not find a client with these details"})
+		return
+	}
+
+	earliest, latest := trainer.BookingWindow(time.Now())
+	if req.ScheduledAt.Before(earliest) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Sessions must be booked at least " + strconv.Itoa(trainer.BookingMinNoticeHours) + " hours in advance",
+		})
+		return
+	}
+	if req.ScheduledAt.After(latest) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Sessions can be booked at most " + strconv.Itoa(trainer.BookingMaxAdvanceDays) + " days in advance",
+		})
+		return
+	}
+
+	// The slot must still be free and inside working hours
+	slot := models.TimeSlot{
+		Start: req.ScheduledAt,
+		End:   req.ScheduledAt.Add(time.Duration(trainer.BookingDurationMinutes) * time.Minute),
+	}
+	windows, err := workingWindows(h.db, trainer, slot.Start.AddDate(0, 0, -1), slot.End.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check availability"})
+		return
+	}
+
+	session := models.Session{
+		ClientID:        client.ID,
+		ScheduledAt:     req.ScheduledAt,
+		DurationMinutes: trainer.BookingDurationMinutes,
+		Status:          models.SessionStatusPending,
+		Notes:           req.Notes,
+	}
+
+	// Check the slot and the package and insert in one transaction, holding a lock on
+	// the trainer so that concurrent requests cannot both take the last place
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&models.Trainer{}, "id = ?", trainer.ID).Error; err != nil {
+			return err
+		}
+
+		busy, err := busySlots(tx, trainer.ID, slot.Start, slot.End)
+		if err != nil {
+			return err
+		}
+		if !services.CoversSlot(services.SubtractSlots(windows, busy), slot) {
+			return errSlotTaken
+		}
+
+		capacity, err := packageCapacity(tx, client)
+		if err != nil {
+			return err
+		}
+		if capacity <= 0 {
+			return errNoSessionsLeft
+		}
+
+		if err := services.MatchProgramDay(tx, &session); err != nil {
+			return err
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		event := models.NewStatusEvent(session, "", models.ClientActor(client.ID), "")
+		return tx.Create(&event).Error
+	})
+	switch {
+	case errors.Is(err, errSlotTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "This slot is no longer available"})
+		return
+	case errors.Is(err, errNoSessionsLeft):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No remaining sessions in your package"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create booking request"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.BookingResponse{
+		ID:              session.ID,
+		ScheduledAt:     session.ScheduledAt,
+		DurationMinutes: session.DurationMinutes,
+		Status:          session.Status,
+	})
+}
+
+// Reasons a booking request is rejected inside its transaction
+var (
+	errSlotTaken      = errors.New("slot is no longer available")
+	errNoSessionsLeft = errors.New("no remaining sessions in package")
+)
+
+// phoneMatches compares phone numbers by digits. A client without a phone on file
+// never matches, since the email alone is not enough to book as them.
+func phoneMatches(stored, given string) bool {
+	digits := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, s)
+	}
+
+	storedDigits := digits(stored)
+	givenDigits := digits(given)
+	if len(storedDigits) < 7 || len(givenDigits) < 7 {
+		return false
+	}
+	// Allow country code / trunk prefix differences
+	return strings.HasSuffix(storedDigits, givenDigits) || strings.HasSuffix(givenDigits, storedDigits)
+}
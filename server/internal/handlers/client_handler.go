@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"ptmate/internal/models"
+	"ptmate/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -44,21 +45,21 @@ func (h *ClientHandler) GetAll(c *gin.Context) {
 		return
 	}
 
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
 	// Build response with calculated stats - initialize as empty slice, not nil
 	responses := make([]models.ClientResponse, 0)
 	for _, client := range clients {
-		stats := h.getSessionStats(client.ID)
-		usedSessions := stats.Completed + stats.NoShow
-		remaining := client.TotalPackageSize - usedSessions
-
-		responses = append(responses, models.ClientResponse{
-			Client:            client,
-			RemainingSessions: remaining,
-			CompletedSessions: stats.Completed,
-			NoShowSessions:    stats.NoShow,
-			CancelledSessions: stats.Cancelled,
-			ScheduledSessions: stats.Scheduled,
-		})
+		response, err := h.buildResponse(client, trainer)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch client packages"})
+			return
+		}
+		responses = append(responses, response)
 	}
 
 	c.JSON(http.StatusOK, responses)
@@ -86,15 +87,38 @@ func (h *ClientHandler) Create(c *gin.Context) {
 		Email:            req.Email,
 		Age:              req.Age,
 		HeightCm:         req.HeightCm,
+		Sex:              req.Sex,
 		TotalPackageSize: req.TotalPackageSize,
 		PackageStartDate: req.PackageStartDate,
 		Notes:            req.Notes,
 	}
 
-	if err := h.db.Create(&client).Error; err != nil {
+	if req.UnitSystem != "" {
+		client.UnitSystem = &req.UnitSystem
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+	if problem := client.ApplyValues(client.EffectiveUnitSystem(trainer), req.Values); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&client).Error; err != nil {
+			return err
+		}
+		// The package size given on creation becomes the client's first package
+		return createTopUpPackage(tx, client, client.TotalPackageSize)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create client"})
 		return
 	}
+	client.Present(client.EffectiveUnitSystem(trainer))
 
 	c.JSON(http.StatusCreated, client)
 }
@@ -123,23 +147,57 @@ func (h *ClientHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	// Calculate session statistics
-	stats := h.getSessionStats(client.ID)
-	usedSessions := stats.Completed + stats.NoShow
-	remaining := client.TotalPackageSize - usedSessions
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
 
-	response := models.ClientResponse{
-		Client:            client,
-		RemainingSessions: remaining,
-		CompletedSessions: stats.Completed,
-		NoShowSessions:    stats.NoShow,
-		CancelledSessions: stats.Cancelled,
-		ScheduledSessions: stats.Scheduled,
+	// Calculate session statistics
+	response, err := h.buildResponse(client, trainer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch client packages"})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetStats returns a client's training streaks and totals in the trainer's timezone
+func (h *ClientHandler) GetStats(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	var client models.Client
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
+	stats, err := services.TrainingStats(h.db, client.ID, trainer.Location(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate client stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // Update updates a client (only if belongs to trainer)
 func (h *ClientHandler) Update(c *gin.Context) {
 	trainerID, ok := getTrainerID(c)
@@ -189,7 +247,24 @@ func (h *ClientHandler) Update(c *gin.Context) {
 	if req.HeightCm != nil {
 		client.HeightCm = req.HeightCm
 	}
+	if req.Sex != nil {
+		client.Sex = *req.Sex
+	}
+	if req.UnitSystem != nil {
+		if *req.UnitSystem == "" {
+			client.UnitSystem = nil
+		} else {
+			client.UnitSystem = req.UnitSystem
+		}
+	}
+	// Package size is derived from the client's packages; raising it sells a top-up package
+	topUp := 0
 	if req.TotalPackageSize != nil {
+		if *req.TotalPackageSize < client.TotalPackageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "total_package_size cannot be reduced; update or cancel a package instead"})
+			return
+		}
+		topUp = *req.TotalPackageSize - client.TotalPackageSize
 		client.TotalPackageSize = *req.TotalPackageSize
 	}
 	if req.PackageStartDate != nil {
@@ -199,10 +274,27 @@ func (h *ClientHandler) Update(c *gin.Context) {
 		client.Notes = *req.Notes
 	}
 
-	if err := h.db.Save(&client).Error; err != nil {
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+	if problem := client.ApplyValues(client.EffectiveUnitSystem(trainer), req.Values); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&client).Error; err != nil {
+			return err
+		}
+		return createTopUpPackage(tx, client, topUp)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update client"})
 		return
 	}
+	client.Present(client.EffectiveUnitSystem(trainer))
 
 	c.JSON(http.StatusOK, client)
 }
@@ -255,15 +347,67 @@ func (h *ClientHandler) GetMeasurements(c *gin.Context) {
 		return
 	}
 
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
 	var measurements []models.Measurement
-	if err := h.db.Where("client_id = ?", id).Order("measured_at DESC").Find(&measurements).Error; err != nil {
+	if err := h.db.Preload("CustomValues.CustomMetric").
+		Where("client_id = ?", id).
+		Order("measured_at DESC").
+		Find(&measurements).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch measurements"})
 		return
 	}
+	for i := range measurements {
+		presentMeasurement(&measurements[i], client, trainer)
+	}
 
 	c.JSON(http.StatusOK, measurements)
 }
 
+// GetMeasurementProgress returns each measurement field's history in the client's
+// unit system, with its changes over time and a smoothed trend line
+func (h *ClientHandler) GetMeasurementProgress(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	var client models.Client
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
+	var measurements []models.Measurement
+	if err := h.db.Where("client_id = ?", id).Order("measured_at ASC").Find(&measurements).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch measurements"})
+		return
+	}
+
+	system := client.EffectiveUnitSystem(trainer)
+	c.JSON(http.StatusOK, models.MeasurementProgressResponse{
+		UnitSystem: system,
+		Fields:     services.MeasurementProgress(measurements, system),
+	})
+}
+
 // CreateMeasurement creates a new measurement for a client
 func (h *ClientHandler) CreateMeasurement(c *gin.Context) {
 	trainerID, ok := getTrainerID(c)
@@ -285,6 +429,12 @@ func (h *ClientHandler) CreateMeasurement(c *gin.Context) {
 		return
 	}
 
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
 	var req models.CreateMeasurementRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -309,51 +459,133 @@ func (h *ClientHandler) CreateMeasurement(c *gin.Context) {
 		Notes:      req.Notes,
 		MeasuredAt: req.MeasuredAt,
 	}
+	metrics, err := trainerCustomMetrics(h.db, trainer.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom metrics"})
+		return
+	}
+	if problem := req.ApplyValues(&measurement, client.EffectiveUnitSystem(trainer), metrics); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
 
 	// Default to current time if not provided
 	if measurement.MeasuredAt.IsZero() {
 		measurement.MeasuredAt = time.Now()
 	}
 
-	if err := h.db.Create(&measurement).Error; err != nil {
+	// A new measurement can achieve the client's goals
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("CustomValues").Create(&measurement).Error; err != nil {
+			return err
+		}
+		if err := saveCustomValues(tx, &measurement); err != nil {
+			return err
+		}
+		return services.RefreshGoals(tx, client.ID, trainer.Location(), time.Now())
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create measurement"})
 		return
 	}
+	presentMeasurement(&measurement, client, trainer)
 
 	c.JSON(http.StatusCreated, measurement)
 }
 
+// buildResponse adds session statistics, package balances and values in the
+// client's unit system to a client
+func (h *ClientHandler) buildResponse(client models.Client, trainer models.Trainer) (models.ClientResponse, error) {
+	stats := h.getSessionStats(client.ID)
+	client.Present(client.EffectiveUnitSystem(trainer))
+
+	balance, err := services.LoadPackageBalance(h.db, client.ID, time.Now())
+	if err != nil {
+		return models.ClientResponse{}, err
+	}
+
+	return models.ClientResponse{
+		Client:                  client,
+		RemainingSessions:       balance.Remaining,
+		CompletedSessions:       stats.Completed,
+		NoShowSessions:          stats.NoShow,
+		CancelledSessions:       stats.Cancelled,
+		LateCancelledSessions:   stats.LateCancelled,
+		ScheduledSessions:       stats.Scheduled,
+		NeedsAttendanceSessions: stats.NeedsAttendance,
+		UnassignedSessions:      balance.Unassigned,
+		Packages:                balance.Packages,
+	}, nil
+}
+
+// createTopUpPackage sells a package of the given size starting now; sizes of 0 are ignored
+func createTopUpPackage(tx *gorm.DB, client models.Client, sessions int) error {
+	if sessions <= 0 {
+		return nil
+	}
+
+	startsAt := time.Now()
+	if client.PackageStartDate != nil && client.PackageStartDate.After(startsAt) {
+		startsAt = *client.PackageStartDate
+	}
+
+	pkg := models.Package{
+		ClientID:     client.ID,
+		SessionCount: sessions,
+		Currency:     models.DefaultCurrency,
+		PurchasedAt:  time.Now(),
+		StartsAt:     startsAt,
+		Status:       models.PackageStatusActive,
+	}
+	if err := tx.Create(&pkg).Error; err != nil {
+		return err
+	}
+	if _, err := services.BackfillPackage(tx, pkg, time.Now()); err != nil {
+		return err
+	}
+	return syncPackageTotal(tx, client.ID)
+}
+
 // sessionStats holds session count by status
 type sessionStats struct {
-	Scheduled int
-	Completed int
-	NoShow    int
-	Cancelled int
+	Scheduled     int
+	Completed     int
+	NoShow        int
+	Cancelled     int
+	LateCancelled int
+	// NeedsAttendance sessions are still scheduled but excluded from Scheduled
+	NeedsAttendance int
 }
 
 // getSessionStats calculates session statistics for a client
 func (h *ClientHandler) getSessionStats(clientID uuid.UUID) sessionStats {
 	var stats struct {
-		Scheduled int64
-		Completed int64
-		NoShow    int64
-		Cancelled int64
+		Scheduled       int64
+		Completed       int64
+		NoShow          int64
+		Cancelled       int64
+		LateCancelled   int64
+		NeedsAttendance int64
 	}
 
 	h.db.Model(&models.Session{}).
 		Where("client_id = ?", clientID).
 		Select(`
-			COUNT(CASE WHEN status = 'scheduled' THEN 1 END) as scheduled,
+			COUNT(CASE WHEN status = 'scheduled' AND NOT needs_attendance THEN 1 END) as scheduled,
 			COUNT(CASE WHEN status = 'completed' THEN 1 END) as completed,
 			COUNT(CASE WHEN status = 'no_show' THEN 1 END) as no_show,
-			COUNT(CASE WHEN status = 'cancelled' THEN 1 END) as cancelled
+			COUNT(CASE WHEN status = 'cancelled' THEN 1 END) as cancelled,
+			COUNT(CASE WHEN status = 'late_cancelled' THEN 1 END) as late_cancelled,
+			COUNT(CASE WHEN status = 'scheduled' AND needs_attendance THEN 1 END) as needs_attendance
 		`).
 		Scan(&stats)
 
 	return sessionStats{
-		Scheduled: int(stats.Scheduled),
-		Completed: int(stats.Completed),
-		NoShow:    int(stats.NoShow),
-		Cancelled: int(stats.Cancelled),
+		Scheduled:       int(stats.Scheduled),
+		Completed:       int(stats.Completed),
+		NoShow:          int(stats.NoShow),
+		Cancelled:       int(stats.Cancelled),
+		LateCancelled:   int(stats.LateCancelled),
+		NeedsAttendance: int(stats.NeedsAttendance),
 	}
 }
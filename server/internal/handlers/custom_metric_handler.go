@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"ptmate/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomMetricHandler handles the trainer's custom measurement metrics
+type CustomMetricHandler struct {
+	db *gorm.DB
+}
+
+// NewCustomMetricHandler creates a new CustomMetricHandler
+func NewCustomMetricHandler(db *gorm.DB) *CustomMetricHandler {
+	return &CustomMetricHandler{db: db}
+}
+
+// GetAll returns the trainer's custom metrics
+func (h *CustomMetricHandler) GetAll(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	metrics, err := trainerCustomMetrics(h.db, trainerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom metrics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+// Create adds a custom metric
+func (h *CustomMetricHandler) Create(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.CreateCustomMetricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	metric := models.CustomMetric{
+		TrainerID: trainerID,
+		Key:       strings.TrimSpace(req.Key),
+		Name:      strings.TrimSpace(req.Name),
+		Unit:      strings.TrimSpace(req.Unit),
+		Decimals:  1,
+		Min:       req.Min,
+		Max:       req.Max,
+	}
+	if metric.Key == "" {
+		metric.Key = models.CustomMetricKey(metric.Name)
+	}
+	if req.Decimals != nil {
+		metric.Decimals = *req.Decimals
+	}
+	if problem := metric.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	var count int64
+	if err := h.db.Model(&models.CustomMetric{}).
+		Where("trainer_id = ? AND key = ?", trainerID, metric.Key).
+		Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check custom metrics"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "A custom metric with key " + metric.Key + " already exists"})
+		return
+	}
+
+	if err := h.db.Create(&metric).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create custom metric"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, metric)
+}
+
+// Update updates a custom metric. Stored values keep the precision they were saved with.
+func (h *CustomMetricHandler) Update(c *gin.Context) {
+	metric, ok := h.findMetric(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCustomMetricRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update only provided fields
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		metric.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Unit != nil {
+		metric.Unit = strings.TrimSpace(*req.Unit)
+	}
+	if req.Decimals != nil {
+		metric.Decimals = *req.Decimals
+	}
+	if req.Min != nil {
+		metric.Min = req.Min
+	}
+	if req.Max != nil {
+		metric.Max = req.Max
+	}
+	if problem := metric.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	if err := h.db.Save(&metric).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update custom metric"})
+		return
+	}
+
+	c.JSON(http.StatusOK, metric)
+}
+
+// Delete soft deletes a custom metric.
+// Its values stay on the measurements but are no longer returned.
+func (h *CustomMetricHandler) Delete(c *gin.Context) {
+	metric, ok := h.findMetric(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(&metric).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete custom metric"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom metric deleted successfully"})
+}
+
+// findMetric loads the trainer's custom metric in the URL.
+// It writes the error response and returns false if it is not found.
+func (h *CustomMetricHandler) findMetric(c *gin.Context) (models.CustomMetric, bool) {
+	var metric models.CustomMetric
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return metric, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid custom metric ID"})
+		return metric, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&metric).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Custom metric not found"})
+		return metric, false
+	}
+
+	return metric, true
+}
+
+// trainerCustomMetrics returns the trainer's custom metrics in the order they were created
+func trainerCustomMetrics(db *gorm.DB, trainerID uuid.UUID) ([]models.CustomMetric, error) {
+	var metrics []models.CustomMetric
+	err := db.Where("trainer_id = ?", trainerID).Order("created_at ASC").Find(&metrics).Error
+	return metrics, err
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"ptmate/internal/models"
+	"ptmate/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -41,10 +44,11 @@ type DashboardResponse struct {
 
 // WeeklyStats represents weekly session statistics
 type WeeklyStats struct {
-	Completed int64 `json:"completed"`
-	NoShow    int64 `json:"no_show"`
-	Cancelled int64 `json:"cancelled"`
-	Scheduled int64 `json:"scheduled"`
+	Completed     int64 `json:"completed"`
+	NoShow        int64 `json:"no_show"`
+	Cancelled     int64 `json:"cancelled"`
+	LateCancelled int64 `json:"late_cancelled"`
+	Scheduled     int64 `json:"scheduled"`
 }
 
 // GetDashboard returns dashboard data for the authenticated trainer
@@ -89,6 +93,7 @@ func (h *DashboardHandler) GetDashboard(c *gin.Context) {
 			COUNT(CASE WHEN sessions.status = 'completed' THEN 1 END) as completed,
 			COUNT(CASE WHEN sessions.status = 'no_show' THEN 1 END) as no_show,
 			COUNT(CASE WHEN sessions.status = 'cancelled' THEN 1 END) as cancelled,
+			COUNT(CASE WHEN sessions.status = 'late_cancelled' THEN 1 END) as late_cancelled,
 			COUNT(CASE WHEN sessions.status = 'scheduled' THEN 1 END) as scheduled
 		`).
 		Scan(&response.WeeklyStats)
@@ -107,7 +112,8 @@ func (h *DashboardHandler) GetDashboard(c *gin.Context) {
 
 // CalendarResponse represents calendar data
 type CalendarResponse struct {
-	Sessions []models.Session `json:"sessions"`
+	Sessions  []models.Session  `json:"sessions"`
+	FreeSlots []models.TimeSlot `json:"free_slots,omitempty"`
 }
 
 // GetCalendar returns sessions for calendar view
@@ -118,19 +124,35 @@ func (h *DashboardHandler) GetCalendar(c *gin.Context) {
 		return
 	}
 
-	// Default to current month if not specified
-	from := c.Query("from")
-	to := c.Query("to")
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+	loc := trainer.Location()
+
+	// from and to are timestamps or YYYY-MM-DD dates; either can be left open.
+	// A to date includes the whole day, as for free-slot searches.
+	from, err := parseCalendarTime(c.Query("from"), loc, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from: " + err.Error()})
+		return
+	}
+	to, err := parseCalendarTime(c.Query("to"), loc, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to: " + err.Error()})
+		return
+	}
 
 	query := h.db.Preload("Client").
 		Joins("JOIN clients ON clients.id = sessions.client_id").
 		Where("clients.trainer_id = ?", trainerID)
 
-	if from != "" {
+	if !from.IsZero() {
 		query = query.Where("sessions.scheduled_at >= ?", from)
 	}
-	if to != "" {
-		query = query.Where("sessions.scheduled_at <= ?", to)
+	if !to.IsZero() {
+		query = query.Where("sessions.scheduled_at < ?", to)
 	}
 
 	var sessions []models.Session
@@ -139,5 +161,102 @@ func (h *DashboardHandler) GetCalendar(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, CalendarResponse{Sessions: sessions})
+	response := CalendarResponse{Sessions: sessions}
+
+	// Optionally include free slots over the same range (next 7 days where it is open)
+	if c.Query("include_free_slots") == "true" {
+		slots := slotQuery{From: from, To: to}
+		if slots.From.IsZero() {
+			now := time.Now().In(loc)
+			slots.From = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		}
+		if slots.To.IsZero() {
+			slots.To = slots.From.AddDate(0, 0, 7)
+		}
+		if err := slots.parseOptions(c); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		response.FreeSlots, err = slots.find(h.db, trainer)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate free slots"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseCalendarTime reads a calendar bound given as an RFC 3339 timestamp, or a
+// local date or date-time in loc. A date as the end of the range includes the
+// whole day. An empty value returns the zero time.
+func parseCalendarTime(value string, loc *time.Location, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := parseRangeDate(value, loc, end); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp or YYYY-MM-DD")
+}
+
+// GetAnalytics returns client retention, churn and adherence metrics over the
+// last `days` days (default 90), flagging clients at risk by the trainer's thresholds
+func (h *DashboardHandler) GetAnalytics(c *gin.Context) {
+	trainerID, ok := h.getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	days := 90
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 7 || parsed > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 7 and 365"})
+			return
+		}
+		days = parsed
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
+	now := time.Now()
+	response := models.AnalyticsResponse{
+		From:       now.AddDate(0, 0, -days),
+		To:         now,
+		Thresholds: trainer.RetentionThresholds(),
+	}
+
+	clients, err := services.ClientRetention(h.db, trainerID, response.From, now, response.Thresholds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate analytics"})
+		return
+	}
+	response.Trainer = models.SummariseRetention(clients)
+
+	// Optionally list only the clients at risk
+	response.Clients = clients
+	if c.Query("at_risk") == "true" {
+		response.Clients = make([]models.ClientMetrics, 0)
+		for _, m := range clients {
+			if m.AtRisk {
+				response.Clients = append(response.Clients, m)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCalendarTime(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		end   bool
+		want  time.Time
+	}{
+		{"empty", "", true, time.Time{}},
+		{"date as start", "2026-03-15", false, time.Date(2026, time.March, 15, 0, 0, 0, 0, istanbul)},
+		{"date as end includes the day", "2026-03-15", true, time.Date(2026, time.March, 16, 0, 0, 0, 0, istanbul)},
+		{"local date-time", "2026-03-15T18:30", true, time.Date(2026, time.March, 15, 18, 30, 0, 0, istanbul)},
+		{"timestamp", "2026-03-15T18:30:00Z", true, time.Date(2026, time.March, 15, 18, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCalendarTime(tt.value, istanbul, tt.end)
+			if err != nil {
+				t.Fatalf("parseCalendarTime(%q) failed: %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseCalendarTime(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseCalendarTime("15.03.2026", istanbul, false); err == nil {
+		t.Error("parseCalendarTime() accepted a date in another format")
+	}
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"ptmate/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExerciseHandler handles exercise library HTTP requests
+type ExerciseHandler struct {
+	db *gorm.DB
+}
+
+// NewExerciseHandler creates a new ExerciseHandler
+func NewExerciseHandler(db *gorm.DB) *ExerciseHandler {
+	return &ExerciseHandler{db: db}
+}
+
+// GetAll returns the global library and the trainer's custom exercises.
+// Supports q (name search in any language), muscle, equipment, pattern and
+// source (global or custom) filters.
+func (h *ExerciseHandler) GetAll(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	query := visibleExercises(h.db, trainerID)
+
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		pattern := "%" + escapeLike(q) + "%"
+		query = query.Where("exercises.name ILIKE ? OR exercises.translations->'tr'->>'name' ILIKE ?", pattern, pattern)
+	}
+	if muscle := c.Query("muscle"); muscle != "" {
+		// jsonb containment, e.g. primary_muscles @> '["quads"]'
+		value, _ := json.Marshal([]string{muscle})
+		query = query.Where("exercises.primary_muscles @> ?::jsonb OR exercises.secondary_muscles @> ?::jsonb",
+			string(value), string(value))
+	}
+	if equipment := c.Query("equipment"); equipment != "" {
+		query = query.Where("exercises.equipment = ?", equipment)
+	}
+	if pattern := c.Query("pattern"); pattern != "" {
+		query = query.Where("exercises.movement_pattern = ?", pattern)
+	}
+	switch c.Query("source") {
+	case "":
+	case "global":
+		query = query.Where("exercises.trainer_id IS NULL")
+	case "custom":
+		query = query.Where("exercises.trainer_id IS NOT NULL")
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source must be global or custom"})
+		return
+	}
+
+	var exercises []models.Exercise
+	if err := query.Order("exercises.name ASC").Find(&exercises).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exercises"})
+		return
+	}
+
+	c.JSON(http.StatusOK, exercises)
+}
+
+// GetOptions returns the muscle groups, equipment and movement patterns exercises can use
+func (h *ExerciseHandler) GetOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"muscle_groups":     models.MuscleGroups,
+		"equipment":         models.Equipment,
+		"movement_patterns": models.MovementPatterns,
+		"languages":         []string{models.LanguageEnglish, models.LanguageTurkish},
+	})
+}
+
+// Create adds a custom exercise for the trainer
+func (h *ExerciseHandler) Create(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.CreateExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	exercise := models.Exercise{
+		TrainerID:        &trainerID,
+		Name:             strings.TrimSpace(req.Name),
+		PrimaryMuscles:   req.PrimaryMuscles,
+		SecondaryMuscles: req.SecondaryMuscles,
+		Equipment:        req.Equipment,
+		MovementPattern:  req.MovementPattern,
+		Translations:     req.Translations,
+	}
+	if problem := exercise.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+	normalizeExercise(&exercise)
+
+	if err := h.db.Create(&exercise).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create exercise"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, exercise)
+}
+
+// GetByID returns an exercise from the global library or the trainer's own
+func (h *ExerciseHandler) GetByID(c *gin.Context) {
+	exercise, ok := h.findExercise(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, exercise)
+}
+
+// Update updates one of the trainer's custom exercises
+func (h *ExerciseHandler) Update(c *gin.Context) {
+	exercise, ok := h.findCustomExercise(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update only provided fields
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		exercise.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.PrimaryMuscles != nil {
+		if len(req.PrimaryMuscles) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "At least one primary muscle group is required"})
+			return
+		}
+		exercise.PrimaryMuscles = req.PrimaryMuscles
+	}
+	if req.SecondaryMuscles != nil {
+		exercise.SecondaryMuscles = req.SecondaryMuscles
+	}
+	if req.Equipment != nil {
+		exercise.Equipment = *req.Equipment
+	}
+	if req.MovementPattern != nil {
+		exercise.MovementPattern = *req.MovementPattern
+	}
+	if req.Translations != nil {
+		exercise.Translations = req.Translations
+	}
+	if problem := exercise.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+	normalizeExercise(&exercise)
+
+	if err := h.db.Save(&exercise).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update exercise"})
+		return
+	}
+
+	c.JSON(http.StatusOK, exercise)
+}
+
+// Delete deletes one of the trainer's custom exercises.
+// Workout logs keep the exercise name they were saved with.
+func (h *ExerciseHandler) Delete(c *gin.Context) {
+	exercise, ok := h.findCustomExercise(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(&exercise).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete exercise"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Exercise deleted successfully"})
+}
+
+// findExercise loads the exercise in the URL if the trainer can see it.
+// It writes the error response and returns false otherwise.
+func (h *ExerciseHandler) findExercise(c *gin.Context) (models.Exercise, bool) {
+	var exercise models.Exercise
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return exercise, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exercise ID"})
+		return exercise, false
+	}
+
+	if err := visibleExercises(h.db, trainerID).Where("exercises.id = ?", id).First(&exercise).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exercise not found"})
+		return exercise, false
+	}
+
+	return exercise, true
+}
+
+// findCustomExercise is findExercise for changes, which only custom exercises allow
+func (h *ExerciseHandler) findCustomExercise(c *gin.Context) (models.Exercise, bool) {
+	exercise, ok := h.findExercise(c)
+	if !ok {
+		return exercise, false
+	}
+
+	if !exercise.IsCustom() {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Exercises in the global library cannot be changed"})
+		return exercise, false
+	}
+
+	return exercise, true
+}
+
+// visibleExercises selects the global library and the trainer's custom exercises
+func visibleExercises(db *gorm.DB, trainerID uuid.UUID) *gorm.DB {
+	return db.Model(&models.Exercise{}).
+		Where("exercises.trainer_id IS NULL OR exercises.trainer_id = ?", trainerID)
+}
+
+// libraryExerciseNames returns the names of the given exercises that the trainer can see
+func libraryExerciseNames(db *gorm.DB, trainerID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]string, error) {
+	var library []models.Exercise
+	if err := visibleExercises(db, trainerID).Where("exercises.id IN ?", ids).Find(&library).Error; err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(library))
+	for _, e := range library {
+		names[e.ID] = e.Name
+	}
+	return names, nil
+}
+
+// normalizeExercise keeps the English translation in step with the exercise name
+func normalizeExercise(exercise *models.Exercise) {
+	if exercise.Translations == nil {
+		exercise.Translations = map[string]models.ExerciseTranslation{}
+	}
+	english := exercise.Translations[models.LanguageEnglish]
+	english.Name = exercise.Name
+	exercise.Translations[models.LanguageEnglish] = english
+
+	if exercise.SecondaryMuscles == nil {
+		exercise.SecondaryMuscles = []string{}
+	}
+}
+
+// escapeLike escapes the LIKE wildcards in user input so they match literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
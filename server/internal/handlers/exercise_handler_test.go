@@ -0,0 +1,23 @@
+package handlers
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"squat", "squat"},
+		{"100%", `100\%`},
+		{"push_up", `push\_up`},
+		{`back\slash`, `back\\slash`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := escapeLike(tt.in); got != tt.want {
+				t.Errorf("escapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
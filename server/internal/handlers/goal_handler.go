@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GoalHandler handles client goal HTTP requests
+type GoalHandler struct {
+	db *gorm.DB
+}
+
+// NewGoalHandler creates a new GoalHandler
+func NewGoalHandler(db *gorm.DB) *GoalHandler {
+	return &GoalHandler{db: db}
+}
+
+// GetByClient returns a client's goals with their progress, optionally filtered by status
+func (h *GoalHandler) GetByClient(c *gin.Context) {
+	client, trainer, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var goals []models.Goal
+	if err := h.db.Where("client_id = ?", client.ID).Order("deadline ASC").Find(&goals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goals"})
+		return
+	}
+
+	// Evaluate before filtering, since evaluation can change a goal's status
+	if err := services.EvaluateGoals(h.db, goals, trainer.Location(), time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate goal progress"})
+		return
+	}
+
+	status := models.GoalStatus(c.Query("status"))
+	system := client.EffectiveUnitSystem(trainer)
+	filtered := make([]models.Goal, 0, len(goals))
+	for i := range goals {
+		if status != "" && goals[i].Status != status {
+			continue
+		}
+		goals[i].Present(system)
+		filtered = append(filtered, goals[i])
+	}
+
+	c.JSON(http.StatusOK, filtered)
+}
+
+// Create adds a goal for a client
+func (h *GoalHandler) Create(c *gin.Context) {
+	client, trainer, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	system, ok := requestUnitSystem(c, req.UnitSystem, client, trainer)
+	if !ok {
+		return
+	}
+
+	goal := models.Goal{
+		ClientID: client.ID,
+		Title:    strings.TrimSpace(req.Title),
+		Kind:     req.Kind,
+		Target:   *req.Target,
+		Status:   models.GoalStatusActive,
+		Notes:    req.Notes,
+	}
+
+	now := time.Now().In(trainer.Location())
+	goal.StartDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date, expected YYYY-MM-DD"})
+			return
+		}
+		goal.StartDate = startDate
+	}
+	deadline, err := time.Parse("2006-01-02", req.Deadline)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deadline, expected YYYY-MM-DD"})
+		return
+	}
+	if !deadline.After(goal.StartDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "deadline must be after start_date"})
+		return
+	}
+	goal.Deadline = deadline
+
+	switch req.Kind {
+	case models.GoalKindMeasurement:
+		field, found := models.MeasurementFieldByKey(req.Field)
+		if !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown measurement field: " + req.Field})
+			return
+		}
+		goal.Field = field.Key
+		goal.Target = system.ToMetric(field.Dimension, goal.Target)
+		if req.Baseline != nil {
+			goal.Baseline = system.ToMetric(field.Dimension, *req.Baseline)
+		} else {
+			baseline, found, err := latestMeasurementValue(h.db, client.ID, field)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch measurements"})
+				return
+			}
+			if !found {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No " + field.Key + " measurement to start from; give a baseline"})
+				return
+			}
+			goal.Baseline = baseline
+		}
+	case models.GoalKindSessions:
+		if goal.Target < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Session goals need a target of at least 1 session"})
+			return
+		}
+	case models.GoalKindCustom:
+		if req.Baseline == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Custom goals need a baseline"})
+			return
+		}
+		goal.Baseline = *req.Baseline
+		goal.Unit = strings.TrimSpace(req.Unit)
+	}
+
+	if goal.Target == goal.Baseline {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target must differ from the baseline"})
+		return
+	}
+
+	if err := h.db.Create(&goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create goal"})
+		return
+	}
+
+	h.respondGoal(c, http.StatusCreated, goal, client, trainer)
+}
+
+// GetByID returns a goal with its progress
+func (h *GoalHandler) GetByID(c *gin.Context) {
+	goal, client, trainer, ok := h.findGoal(c)
+	if !ok {
+		return
+	}
+
+	h.respondGoal(c, http.StatusOK, goal, client, trainer)
+}
+
+// Update updates a goal. Custom goals take their new current value here.
+func (h *GoalHandler) Update(c *gin.Context) {
+	goal, client, trainer, ok := h.findGoal(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	system, ok := requestUnitSystem(c, req.UnitSystem, client, trainer)
+	if !ok {
+		return
+	}
+
+	// Update only provided fields
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Title cannot be empty"})
+			return
+		}
+		goal.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Target != nil {
+		goal.Target = *req.Target
+		if field, found := goal.MeasurementField(); found {
+			goal.Target = system.ToMetric(field.Dimension, goal.Target)
+		}
+		if goal.Target == goal.Baseline {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "target must differ from the baseline"})
+			return
+		}
+	}
+	if req.Deadline != nil {
+		deadline, err := time.Parse("2006-01-02", *req.Deadline)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deadline, expected YYYY-MM-DD"})
+			return
+		}
+		if !deadline.After(goal.StartDate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "deadline must be after start_date"})
+			return
+		}
+		goal.Deadline = deadline
+	}
+	if req.Status != nil {
+		// Achieved and missed are decided from progress; reactivating re-evaluates the goal
+		if *req.Status != models.GoalStatusActive && *req.Status != models.GoalStatusAbandoned {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status can only be set to active or abandoned"})
+			return
+		}
+		goal.Status = *req.Status
+		if goal.Status == models.GoalStatusActive {
+			goal.AchievedAt = nil
+		}
+	}
+	if req.CustomValue != nil {
+		if goal.Kind != models.GoalKindCustom {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only custom goals take a current value"})
+			return
+		}
+		now := time.Now()
+		goal.CustomValue = req.CustomValue
+		goal.CustomValueAt = &now
+	}
+	if req.Notes != nil {
+		goal.Notes = *req.Notes
+	}
+
+	if err := h.db.Save(&goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update goal"})
+		return
+	}
+
+	h.respondGoal(c, http.StatusOK, goal, client, trainer)
+}
+
+// Delete soft deletes a goal
+func (h *GoalHandler) Delete(c *gin.Context) {
+	goal, _, _, ok := h.findGoal(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(&goal).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+// findClient loads the client in the URL and its trainer.
+// It writes the error response and returns false if the client does not belong to the trainer.
+func (h *GoalHandler) findClient(c *gin.Context) (models.Client, models.Trainer, bool) {
+	var client models.Client
+	var trainer models.Trainer
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return client, trainer, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return client, trainer, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return client, trainer, false
+	}
+
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return client, trainer, false
+	}
+
+	return client, trainer, true
+}
+
+// findGoal loads the goal in the URL with its client and trainer.
+// It writes the error response and returns false if the goal does not belong to the trainer.
+func (h *GoalHandler) findGoal(c *gin.Context) (models.Goal, models.Client, models.Trainer, bool) {
+	var goal models.Goal
+	var client models.Client
+	var trainer models.Trainer
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return goal, client, trainer, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return goal, client, trainer, false
+	}
+
+	if err := h.db.Joins("JOIN clients ON clients.id = goals.client_id").
+		Where("goals.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&goal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return goal, client, trainer, false
+	}
+
+	if err := h.db.First(&client, "id = ?", goal.ClientID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return goal, client, trainer, false
+	}
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return goal, client, trainer, false
+	}
+
+	return goal, client, trainer, true
+}
+
+// respondGoal evaluates a goal and writes it in the client's unit system
+func (h *GoalHandler) respondGoal(c *gin.Context, status int, goal models.Goal, client models.Client, trainer models.Trainer) {
+	goals := []models.Goal{goal}
+	if err := services.EvaluateGoals(h.db, goals, trainer.Location(), time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate goal progress"})
+		return
+	}
+	goals[0].Present(client.EffectiveUnitSystem(trainer))
+
+	c.JSON(status, goals[0])
+}
+
+// requestUnitSystem returns the unit system a request's values are in: the one
+// given, or else the client's. It writes the error response and returns false if invalid.
+func requestUnitSystem(c *gin.Context, given models.UnitSystem, client models.Client, trainer models.Trainer) (models.UnitSystem, bool) {
+	if given == "" {
+		return client.EffectiveUnitSystem(trainer), true
+	}
+	if !models.IsValidUnitSystem(given) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unit_system must be metric or imperial"})
+		return given, false
+	}
+	return given, true
+}
+
+// latestMeasurementValue returns the client's most recent value of a measurement field
+func latestMeasurementValue(db *gorm.DB, clientID uuid.UUID, field models.MeasurementField) (float64, bool, error) {
+	var measurements []models.Measurement
+	if err := db.Where("client_id = ?", clientID).Order("measured_at DESC").Find(&measurements).Error; err != nil {
+		return 0, false, err
+	}
+	for i := range measurements {
+		if value := field.Get(&measurements[i]); value != nil {
+			return *value, true, nil
+		}
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InvoiceHandler handles invoice and receipt HTTP requests
+type InvoiceHandler struct {
+	db *gorm.DB
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler
+func NewInvoiceHandler(db *gorm.DB) *InvoiceHandler {
+	return &InvoiceHandler{db: db}
+}
+
+// Create issues an invoice (or receipt) for a package purchase and stores its PDF
+func (h *InvoiceHandler) Create(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	packageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		return
+	}
+
+	var pkg models.Package
+	if err := h.db.Joins("JOIN clients ON clients.id = packages.client_id").
+		Where("packages.id = ? AND clients.trainer_id = ?", packageID, trainerID).
+		First(&pkg).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		return
+	}
+
+	var req models.CreateInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default to an invoice
+	if req.Kind == "" {
+		req.Kind = models.InvoiceKindInvoice
+	}
+	if req.Kind != models.InvoiceKindInvoice && req.Kind != models.InvoiceKindReceipt {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice kind"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+	var client models.Client
+	if err := h.db.First(&client, "id = ?", pkg.ClientID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	price := pkg.Price
+	if req.Price != nil {
+		price = *req.Price
+	}
+	subtotal := math.Round(price*100) / 100
+	taxAmount := math.Round(subtotal*req.TaxRate) / 100
+
+	sellerName := trainer.BusinessName
+	if sellerName == "" {
+		sellerName = trainer.FirstName + " " + trainer.LastName
+	}
+
+	invoice := models.Invoice{
+		TrainerID:       trainerID,
+		ClientID:        client.ID,
+		PackageID:       pkg.ID,
+		Kind:            req.Kind,
+		IssuedAt:        time.Now(),
+		SellerName:      sellerName,
+		SellerAddress:   trainer.BusinessAddress,
+		SellerPhone:     trainer.BusinessPhone,
+		SellerEmail:     trainer.Email,
+		SellerTaxOffice: trainer.TaxOffice,
+		SellerTaxNumber: trainer.TaxNumber,
+		BuyerName:       strings.TrimSpace(client.FirstName + " " + client.LastName),
+		BuyerEmail:      client.Email,
+		BuyerPhone:      client.Phone,
+		Items: []models.InvoiceItem{{
+			Description: fmt.Sprintf("Personal training package (%d sessions)", pkg.SessionCount),
+			Quantity:    1,
+			UnitPrice:   subtotal,
+			Total:       subtotal,
+		}},
+		Currency:  pkg.Currency,
+		Subtotal:  subtotal,
+		TaxRate:   req.TaxRate,
+		TaxAmount: taxAmount,
+		Total:     math.Round((subtotal+taxAmount)*100) / 100,
+		Notes:     req.Notes,
+	}
+	if req.IssuedAt != nil {
+		invoice.IssuedAt = *req.IssuedAt
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the trainer so concurrent invoices get consecutive numbers
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").First(&models.Trainer{}, "id = ?", trainerID).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&models.Invoice{}).
+			Where("trainer_id = ?", trainerID).
+			Select("COALESCE(MAX(sequence), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		invoice.Sequence = last + 1
+		invoice.Number = models.InvoiceNumber(invoice.Sequence)
+
+		if err := tx.Create(&invoice).Error; err != nil {
+			return err
+		}
+
+		document := models.InvoiceDocument{
+			InvoiceID: invoice.ID,
+			Content:   services.RenderInvoicePDF(invoice, trainer.Location()),
+		}
+		return tx.Create(&document).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue invoice"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// GetByClient returns a client's invoices, newest first
+func (h *InvoiceHandler) GetByClient(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	var invoices []models.Invoice
+	if err := h.db.Where("client_id = ? AND trainer_id = ?", clientID, trainerID).
+		Order("sequence DESC").
+		Find(&invoices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invoices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoices)
+}
+
+// GetByID returns an invoice
+func (h *InvoiceHandler) GetByID(c *gin.Context) {
+	invoice, ok := h.findInvoice(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// DownloadPDF returns the stored PDF of an invoice
+func (h *InvoiceHandler) DownloadPDF(c *gin.Context) {
+	invoice, ok := h.findInvoice(c)
+	if !ok {
+		return
+	}
+
+	var document models.InvoiceDocument
+	if err := h.db.First(&document, "invoice_id = ?", invoice.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice document not found"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, invoice.Number))
+	c.Data(http.StatusOK, "application/pdf", document.Content)
+}
+
+// findInvoice loads the invoice in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *InvoiceHandler) findInvoice(c *gin.Context) (models.Invoice, bool) {
+	var invoice models.Invoice
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return invoice, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return invoice, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&invoice).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return invoice, false
+	}
+
+	return invoice, true
+}
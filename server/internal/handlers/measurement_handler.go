@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"ptmate/internal/models"
+	"ptmate/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MeasurementHandler handles measurement-related HTTP requests
@@ -29,7 +32,7 @@ func (h *MeasurementHandler) GetByID(c *gin.Context) {
 	}
 
 	var measurement models.Measurement
-	if err := h.db.First(&measurement, id).Error; err != nil {
+	if err := h.db.Preload("CustomValues.CustomMetric").First(&measurement, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Measurement not found"})
 			return
@@ -38,6 +41,13 @@ func (h *MeasurementHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	client, trainer, err := measurementOwner(h.db, measurement)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch client"})
+		return
+	}
+	presentMeasurement(&measurement, client, trainer)
+
 	c.JSON(http.StatusOK, measurement)
 }
 
@@ -49,13 +59,31 @@ func (h *MeasurementHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	result := h.db.Delete(&models.Measurement{}, id)
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete measurement"})
+	var measurement models.Measurement
+	if err := h.db.First(&measurement, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Measurement not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch measurement"})
 		return
 	}
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Measurement not found"})
+
+	client, trainer, err := measurementOwner(h.db, measurement)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch client"})
+		return
+	}
+
+	// Goals the measurement achieved may no longer be
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&measurement).Error; err != nil {
+			return err
+		}
+		return services.RefreshGoals(tx, client.ID, trainer.Location(), time.Now())
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete measurement"})
 		return
 	}
 
@@ -71,7 +99,7 @@ func (h *MeasurementHandler) Update(c *gin.Context) {
 	}
 
 	var measurement models.Measurement
-	if err := h.db.First(&measurement, id).Error; err != nil {
+	if err := h.db.Preload("CustomValues.CustomMetric").First(&measurement, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Measurement not found"})
 			return
@@ -86,6 +114,12 @@ func (h *MeasurementHandler) Update(c *gin.Context) {
 		return
 	}
 
+	client, trainer, err := measurementOwner(h.db, measurement)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch client"})
+		return
+	}
+
 	// Update fields
 	measurement.Title = req.Title
 	measurement.WeightKg = req.WeightKg
@@ -104,11 +138,86 @@ func (h *MeasurementHandler) Update(c *gin.Context) {
 	if !req.MeasuredAt.IsZero() {
 		measurement.MeasuredAt = req.MeasuredAt
 	}
+	metrics, err := trainerCustomMetrics(h.db, trainer.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch custom metrics"})
+		return
+	}
+	if problem := req.ApplyValues(&measurement, client.EffectiveUnitSystem(trainer), metrics); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
 
-	if err := h.db.Save(&measurement).Error; err != nil {
+	// The corrected values can achieve the client's goals or undo them
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("CustomValues").Save(&measurement).Error; err != nil {
+			return err
+		}
+		if err := saveCustomValues(tx, &measurement); err != nil {
+			return err
+		}
+		return services.RefreshGoals(tx, client.ID, trainer.Location(), time.Now())
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update measurement"})
 		return
 	}
 
+	presentMeasurement(&measurement, client, trainer)
+
 	c.JSON(http.StatusOK, measurement)
 }
+
+// measurementOwner loads the client a measurement belongs to and the client's trainer
+func measurementOwner(db *gorm.DB, measurement models.Measurement) (models.Client, models.Trainer, error) {
+	var client models.Client
+	var trainer models.Trainer
+	if err := db.Unscoped().First(&client, "id = ?", measurement.ClientID).Error; err != nil {
+		return client, trainer, err
+	}
+	err := db.First(&trainer, "id = ?", client.TrainerID).Error
+	return client, trainer, err
+}
+
+// saveCustomValues stores the custom metric values of a measurement, replacing the
+// stored values of the same metrics and leaving those of other metrics alone
+func saveCustomValues(tx *gorm.DB, measurement *models.Measurement) error {
+	if len(measurement.CustomValues) == 0 {
+		return nil
+	}
+	for i := range measurement.CustomValues {
+		measurement.CustomValues[i].MeasurementID = measurement.ID
+	}
+	return tx.Omit("ID", "CustomMetric").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "measurement_id"}, {Name: "custom_metric_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value"}),
+	}).Create(&measurement.CustomValues).Error
+}
+
+// presentMeasurement fills in a measurement's derived values and its values
+// in the client's unit system
+func presentMeasurement(measurement *models.Measurement, client models.Client, trainer models.Trainer) {
+	measurement.Derive(client)
+	measurement.Present(client.EffectiveUnitSystem(trainer))
+}
+
+// clientUnitSystem returns the unit system a client's values are shown in
+func clientUnitSystem(db *gorm.DB, clientID uuid.UUID) (models.UnitSystem, error) {
+	var client models.Client
+	if err := db.Select("id", "trainer_id", "unit_system").First(&client, "id = ?", clientID).Error; err != nil {
+		return "", err
+	}
+	var trainer models.Trainer
+	if err := db.Select("id", "unit_system").First(&trainer, "id = ?", client.TrainerID).Error; err != nil {
+		return "", err
+	}
+	return client.EffectiveUnitSystem(trainer), nil
+}
+
+// orUnitSystem returns the unit system given in a request, or else the client's
+func orUnitSystem(given, client models.UnitSystem) models.UnitSystem {
+	if given == "" {
+		return client
+	}
+	return given
+}
@@ -0,0 +1,521 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PackageHandler handles session package HTTP requests
+type PackageHandler struct {
+	db *gorm.DB
+}
+
+// NewPackageHandler creates a new PackageHandler
+func NewPackageHandler(db *gorm.DB) *PackageHandler {
+	return &PackageHandler{db: db}
+}
+
+// GetByClient returns a client's packages with their usage, oldest first
+func (h *PackageHandler) GetByClient(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	// Verify client belongs to trainer
+	var client models.Client
+	if err := h.db.Where("id = ? AND trainer_id = ?", clientID, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	balance, err := services.LoadPackageBalance(h.db, client.ID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch packages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, balance.Packages)
+}
+
+// Create sells a new package to a client
+func (h *PackageHandler) Create(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	// Verify client belongs to trainer
+	var client models.Client
+	if err := h.db.Where("id = ? AND trainer_id = ?", clientID, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	var req models.CreatePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	currency, ok := normalizeCurrency(req.Currency)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid currency"})
+		return
+	}
+
+	pkg := models.Package{
+		ClientID:     client.ID,
+		SessionCount: req.SessionCount,
+		Price:        req.Price,
+		Currency:     currency,
+		PurchasedAt:  time.Now(),
+		ExpiresAt:    req.ExpiresAt,
+		Status:       models.PackageStatusActive,
+		Notes:        req.Notes,
+	}
+	if req.PurchasedAt != nil {
+		pkg.PurchasedAt = *req.PurchasedAt
+	}
+
+	// Default start to the purchase date
+	pkg.StartsAt = pkg.PurchasedAt
+	if req.StartsAt != nil {
+		pkg.StartsAt = *req.StartsAt
+	}
+
+	if pkg.ExpiresAt == nil && req.ValidityDays != nil {
+		if *req.ValidityDays <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "validity_days must be positive"})
+			return
+		}
+		expiresAt := pkg.StartsAt.AddDate(0, 0, *req.ValidityDays)
+		pkg.ExpiresAt = &expiresAt
+	}
+	if pkg.ExpiresAt != nil && !pkg.ExpiresAt.After(pkg.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be after starts_at"})
+		return
+	}
+
+	now := time.Now()
+	backfilled := 0
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&pkg).Error; err != nil {
+			return err
+		}
+		// Sessions used while no package had room are taken from the new one
+		if backfilled, err = services.BackfillPackage(tx, pkg, now); err != nil {
+			return err
+		}
+		return syncPackageTotal(tx, client.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create package"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, services.PackageUsage(pkg, backfilled, now))
+}
+
+// GetByID returns a package with its usage and freezes
+func (h *PackageHandler) GetByID(c *gin.Context) {
+	pkg, ok := h.findPackage(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.usage(pkg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch package usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Update updates a package
+func (h *PackageHandler) Update(c *gin.Context) {
+	pkg, ok := h.findPackage(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePackageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	current, err := h.usage(pkg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch package usage"})
+		return
+	}
+
+	// Update only provided fields
+	if req.SessionCount != nil {
+		if *req.SessionCount < current.UsedSessions || *req.SessionCount <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "session_count cannot be less than the sessions already used"})
+			return
+		}
+		pkg.SessionCount = *req.SessionCount
+	}
+	priceChanged := false
+	if req.Price != nil {
+		if *req.Price < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "price cannot be negative"})
+			return
+		}
+		priceChanged = math.Round(*req.Price*100) != math.Round(pkg.Price*100)
+		pkg.Price = *req.Price
+	}
+	if req.Currency != nil {
+		currency, ok := normalizeCurrency(*req.Currency)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid currency"})
+			return
+		}
+		if currency != pkg.Currency {
+			// Payments stay in the currency they were made in
+			var payments int64
+			if err := h.db.Model(&models.Payment{}).Where("package_id = ?", pkg.ID).Count(&payments).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch package payments"})
+				return
+			}
+			if payments > 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": "The currency cannot be changed after payments were recorded for the package"})
+				return
+			}
+		}
+		pkg.Currency = currency
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(pkg.StartsAt) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be after starts_at"})
+			return
+		}
+		pkg.ExpiresAt = req.ExpiresAt
+	}
+	if req.Status != nil && *req.Status != pkg.Status {
+		// Freezing goes through the freeze endpoints so the expiry is extended
+		if *req.Status != models.PackageStatusCancelled {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status can only be changed to cancelled; use freeze/unfreeze instead"})
+			return
+		}
+		pkg.Status = *req.Status
+	}
+	if req.Notes != nil {
+		pkg.Notes = *req.Notes
+	}
+
+	now := time.Now()
+	backfilled := 0
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Freezes").Save(&pkg).Error; err != nil {
+			return err
+		}
+		if priceChanged {
+			if err := rebalanceInstallments(tx, pkg); err != nil {
+				return err
+			}
+		}
+		// More sessions or a later expiry can make room for unassigned sessions
+		if backfilled, err = services.BackfillPackage(tx, pkg, now); err != nil {
+			return err
+		}
+		return syncPackageTotal(tx, pkg.ClientID)
+	})
+	if errors.Is(err, errInstallmentsOverPrice) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The new price does not cover the installments before the last one; replace the payment plan first"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update package"})
+		return
+	}
+
+	c.JSON(http.StatusOK, services.PackageUsage(pkg, current.UsedSessions+backfilled, now))
+}
+
+// Freeze pauses an active package until it is unfrozen
+func (h *PackageHandler) Freeze(c *gin.Context) {
+	pkg, ok := h.findPackage(c)
+	if !ok {
+		return
+	}
+
+	var req models.FreezePackageRequest
+	// Body is optional, the freeze starts now by default
+	_ = c.ShouldBindJSON(&req)
+
+	now := time.Now()
+	if pkg.Status != models.PackageStatusActive || pkg.IsExpired(now) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only active packages can be frozen"})
+		return
+	}
+
+	freeze := models.PackageFreeze{
+		PackageID: pkg.ID,
+		StartsAt:  now,
+		Reason:    req.Reason,
+	}
+	if req.StartsAt != nil {
+		if req.StartsAt.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "starts_at cannot be in the future"})
+			return
+		}
+		freeze.StartsAt = *req.StartsAt
+	}
+
+	pkg.Status = models.PackageStatusFrozen
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&freeze).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Freezes").Save(&pkg).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to freeze package"})
+		return
+	}
+
+	c.JSON(http.StatusOK, freeze)
+}
+
+// Unfreeze resumes a frozen package and extends its expiry by the frozen time
+func (h *PackageHandler) Unfreeze(c *gin.Context) {
+	pkg, ok := h.findPackage(c)
+	if !ok {
+		return
+	}
+
+	if pkg.Status != models.PackageStatusFrozen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Package is not frozen"})
+		return
+	}
+
+	var freeze models.PackageFreeze
+	if err := h.db.Where("package_id = ? AND ends_at IS NULL", pkg.ID).
+		Order("starts_at DESC").
+		First(&freeze).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Package has no open freeze"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch open freeze"})
+		return
+	}
+
+	now := time.Now()
+	pkg.Unfreeze(&freeze, now)
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&freeze).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Freezes").Save(&pkg).Error; err != nil {
+			return err
+		}
+		_, err := services.BackfillPackage(tx, pkg, now)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfreeze package"})
+		return
+	}
+
+	// Reload so the response shows the closed freeze
+	var updated models.Package
+	if err := h.db.Preload("Freezes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at ASC")
+	}).First(&updated, "id = ?", pkg.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch package"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// GetInstallments returns a package's payment plan, earliest first
+func (h *PackageHandler) GetInstallments(c *gin.Context) {
+	pkg, ok := h.findPackage(c)
+	if !ok {
+		return
+	}
+
+	var installments []models.PackageInstallment
+	if err := h.db.Where("package_id = ?", pkg.ID).Order("due_at ASC").Find(&installments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch installments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, installments)
+}
+
+// SetInstallments replaces a package's payment plan
+func (h *PackageHandler) SetInstallments(c *gin.Context) {
+	pkg, ok := h.findPackage(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetInstallmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	installments := make([]models.PackageInstallment, 0, len(req.Installments))
+	total := 0.0
+	for _, entry := range req.Installments {
+		installments = append(installments, models.PackageInstallment{
+			PackageID: pkg.ID,
+			DueAt:     entry.DueAt,
+			Amount:    entry.Amount,
+		})
+		total += entry.Amount
+	}
+
+	// Compare in cents to avoid float rounding noise
+	if len(installments) > 0 && math.Round(total*100) != math.Round(pkg.Price*100) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Installments must add up to the package price"})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("package_id = ?", pkg.ID).Delete(&models.PackageInstallment{}).Error; err != nil {
+			return err
+		}
+		if len(installments) == 0 {
+			return nil
+		}
+		return tx.Create(&installments).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save installments"})
+		return
+	}
+
+	sort.Slice(installments, func(i, j int) bool {
+		return installments[i].DueAt.Before(installments[j].DueAt)
+	})
+
+	c.JSON(http.StatusOK, installments)
+}
+
+// findPackage loads the package in the URL if it belongs to one of the trainer's clients.
+// It writes the error response and returns false otherwise.
+func (h *PackageHandler) findPackage(c *gin.Context) (models.Package, bool) {
+	var pkg models.Package
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return pkg, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid package ID"})
+		return pkg, false
+	}
+
+	if err := h.db.Preload("Freezes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at ASC")
+	}).Joins("JOIN clients ON clients.id = packages.client_id").
+		Where("packages.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&pkg).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+		return pkg, false
+	}
+
+	return pkg, true
+}
+
+// usage counts the used sessions taken from a package
+func (h *PackageHandler) usage(pkg models.Package) (models.PackageResponse, error) {
+	var used int64
+	if err := h.db.Model(&models.Session{}).
+		Where("package_id = ? AND status IN ?", pkg.ID, models.UsedStatuses()).
+		Count(&used).Error; err != nil {
+		return models.PackageResponse{}, err
+	}
+	return services.PackageUsage(pkg, int(used), time.Now()), nil
+}
+
+// errInstallmentsOverPrice means a package's price no longer covers its earlier installments
+var errInstallmentsOverPrice = errors.New("installments exceed the package price")
+
+// rebalanceInstallments keeps a package's payment plan adding up to its price
+// by moving the difference onto the last installment
+func rebalanceInstallments(tx *gorm.DB, pkg models.Package) error {
+	var installments []models.PackageInstallment
+	if err := tx.Where("package_id = ?", pkg.ID).Order("due_at ASC").Find(&installments).Error; err != nil {
+		return err
+	}
+	if len(installments) == 0 {
+		return nil
+	}
+
+	// Work in cents to avoid float rounding noise
+	cents := math.Round(pkg.Price * 100)
+	for _, installment := range installments[:len(installments)-1] {
+		cents -= math.Round(installment.Amount * 100)
+	}
+	if cents <= 0 {
+		return errInstallmentsOverPrice
+	}
+
+	last := installments[len(installments)-1]
+	return tx.Model(&last).Update("amount", cents/100).Error
+}
+
+// syncPackageTotal keeps the client's TotalPackageSize equal to the sessions of
+// their packages that were not cancelled
+func syncPackageTotal(tx *gorm.DB, clientID uuid.UUID) error {
+	total := tx.Model(&models.Package{}).
+		Select("COALESCE(SUM(session_count), 0)").
+		Where("client_id = ? AND status <> ?", clientID, models.PackageStatusCancelled)
+	return tx.Model(&models.Client{}).Where("id = ?", clientID).UpdateColumn("total_package_size", total).Error
+}
+
+// normalizeCurrency upper-cases an ISO 4217 code, defaulting to the app currency
+func normalizeCurrency(currency string) (string, bool) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return models.DefaultCurrency, true
+	}
+	if len(currency) != 3 {
+		return "", false
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+	return currency, true
+}
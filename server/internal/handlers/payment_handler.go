@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PaymentHandler handles payment and balance HTTP requests
+type PaymentHandler struct {
+	db *gorm.DB
+}
+
+// NewPaymentHandler creates a new PaymentHandler
+func NewPaymentHandler(db *gorm.DB) *PaymentHandler {
+	return &PaymentHandler{db: db}
+}
+
+// GetByClient returns a client's payments and refunds, newest first
+func (h *PaymentHandler) GetByClient(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var payments []models.Payment
+	if err := h.db.Where("client_id = ?", client.ID).Order("paid_at DESC").Find(&payments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// Create records a payment or refund for a client
+func (h *PaymentHandler) Create(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default to a payment
+	if req.Type == "" {
+		req.Type = models.PaymentTypePayment
+	}
+	if !models.IsValidPaymentType(req.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment type"})
+		return
+	}
+	if !models.IsValidPaymentMethod(req.Method) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment method"})
+		return
+	}
+
+	currency, ok := normalizeCurrency(req.Currency)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid currency"})
+		return
+	}
+
+	// Payments against a package are made in the package's currency
+	if req.PackageID != nil {
+		var pkg models.Package
+		if err := h.db.Where("id = ? AND client_id = ?", *req.PackageID, client.ID).First(&pkg).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Package not found"})
+			return
+		}
+		if req.Currency == "" {
+			currency = pkg.Currency
+		} else if currency != pkg.Currency {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Currency does not match the package currency"})
+			return
+		}
+	}
+
+	payment := models.Payment{
+		ClientID:  client.ID,
+		PackageID: req.PackageID,
+		Type:      req.Type,
+		Amount:    req.Amount,
+		Currency:  currency,
+		Method:    req.Method,
+		PaidAt:    time.Now(),
+		Notes:     req.Notes,
+	}
+	if req.PaidAt != nil {
+		payment.PaidAt = *req.PaidAt
+	}
+
+	// A refund cannot give back more than the client has paid
+	if payment.Type == models.PaymentTypeRefund {
+		balances, err := services.ClientBalances(h.db, []uuid.UUID{client.ID}, time.Now())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate balance"})
+			return
+		}
+		netPaid := 0.0
+		for _, b := range balances[client.ID] {
+			if b.Currency == currency {
+				netPaid = b.Paid - b.Refunded
+			}
+		}
+		if payment.Amount > netPaid {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Refund exceeds the amount paid"})
+			return
+		}
+	}
+
+	if err := h.db.Create(&payment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record payment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+// Delete soft deletes a payment recorded by mistake
+func (h *PaymentHandler) Delete(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	// Verify payment belongs to trainer's client
+	var payment models.Payment
+	if err := h.db.Joins("JOIN clients ON clients.id = payments.client_id").
+		Where("payments.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&payment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		return
+	}
+
+	if err := h.db.Delete(&payment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete payment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment deleted successfully"})
+}
+
+// GetBalance returns a client's charges, payments and outstanding balance
+func (h *PaymentHandler) GetBalance(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	balances, err := services.ClientBalances(h.db, []uuid.UUID{client.ID}, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newBalanceResponse(client, balances[client.ID]))
+}
+
+// GetOutstanding lists clients who owe money; with overdue=true only those with overdue amounts
+func (h *PaymentHandler) GetOutstanding(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var clients []models.Client
+	if err := h.db.Where("trainer_id = ?", trainerID).Order("first_name ASC, last_name ASC").Find(&clients).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clients"})
+		return
+	}
+
+	clientIDs := make([]uuid.UUID, 0, len(clients))
+	for _, client := range clients {
+		clientIDs = append(clientIDs, client.ID)
+	}
+
+	balances, err := services.ClientBalances(h.db, clientIDs, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate balances"})
+		return
+	}
+
+	overdueOnly := c.Query("overdue") == "true"
+	responses := make([]models.ClientBalanceResponse, 0)
+	for _, client := range clients {
+		response := newBalanceResponse(client, balances[client.ID])
+		include := response.HasOutstanding()
+		if overdueOnly {
+			include = response.HasOverdue()
+		}
+		if !include {
+			continue
+		}
+		responses = append(responses, response)
+	}
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// findClient loads the client in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *PaymentHandler) findClient(c *gin.Context) (models.Client, bool) {
+	var client models.Client
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return client, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return client, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return client, false
+	}
+
+	return client, true
+}
+
+// newBalanceResponse builds a client's balance response
+func newBalanceResponse(client models.Client, balances []models.CurrencyBalance) models.ClientBalanceResponse {
+	if balances == nil {
+		balances = []models.CurrencyBalance{}
+	}
+	return models.ClientBalanceResponse{
+		ClientID:  client.ID,
+		FirstName: client.FirstName,
+		LastName:  client.LastName,
+		Balances:  balances,
+	}
+}
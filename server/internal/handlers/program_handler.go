@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProgramHandler handles training program HTTP requests
+type ProgramHandler struct {
+	db *gorm.DB
+}
+
+// NewProgramHandler creates a new ProgramHandler
+func NewProgramHandler(db *gorm.DB) *ProgramHandler {
+	return &ProgramHandler{db: db}
+}
+
+// GetAll returns the trainer's programs without their contents.
+// template=true lists only templates, template=false only assigned programs.
+func (h *ProgramHandler) GetAll(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	query := h.db.Where("trainer_id = ?", trainerID)
+	switch c.Query("template") {
+	case "true":
+		query = query.Where("client_id IS NULL")
+	case "false":
+		query = query.Where("client_id IS NOT NULL")
+	}
+
+	var programs []models.Program
+	if err := query.Order("created_at DESC").Find(&programs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch programs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, programs)
+}
+
+// GetByClient returns the programs assigned to a client, newest first
+func (h *ProgramHandler) GetByClient(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	var programs []models.Program
+	if err := h.db.Where("trainer_id = ? AND client_id = ?", trainerID, clientID).
+		Order("starts_on DESC").
+		Find(&programs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch programs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, programs)
+}
+
+// Create creates a program template
+func (h *ProgramHandler) Create(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.SaveProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if problem := req.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	program := models.Program{
+		TrainerID:   trainerID,
+		Name:        req.Name,
+		Description: req.Description,
+		Weeks:       req.ProgramWeeks(),
+	}
+	if !h.resolveExercises(c, trainerID, program.Weeks) {
+		return
+	}
+
+	if err := h.db.Create(&program).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create program"})
+		return
+	}
+
+	h.respondProgram(c, http.StatusCreated, program.ID)
+}
+
+// GetByID returns a program with its weeks, days and exercises
+func (h *ProgramHandler) GetByID(c *gin.Context) {
+	program, ok := h.findProgram(c)
+	if !ok {
+		return
+	}
+
+	h.respondProgram(c, http.StatusOK, program.ID)
+}
+
+// Update replaces a program's name, description and contents.
+// Sessions of an assigned program are linked again to the new days.
+func (h *ProgramHandler) Update(c *gin.Context) {
+	program, ok := h.findProgram(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if problem := req.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	weeks := req.ProgramWeeks()
+	if !h.resolveExercises(c, program.TrainerID, weeks) {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteProgramWeeks(tx, program.ID); err != nil {
+			return err
+		}
+
+		program.Name = req.Name
+		program.Description = req.Description
+		if err := tx.Save(&program).Error; err != nil {
+			return err
+		}
+
+		for i := range weeks {
+			weeks[i].ProgramID = program.ID
+		}
+		if len(weeks) > 0 {
+			if err := tx.Create(&weeks).Error; err != nil {
+				return err
+			}
+		}
+
+		if program.ClientID != nil {
+			return services.RelinkProgramSessions(tx, *program.ClientID, time.Now())
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update program"})
+		return
+	}
+
+	h.respondProgram(c, http.StatusOK, program.ID)
+}
+
+// Assign copies a program to a client, starting on the given date,
+// and links the client's sessions to the matching program days
+func (h *ProgramHandler) Assign(c *gin.Context) {
+	source, ok := h.findProgram(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssignProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startsOn, err := time.Parse("2006-01-02", req.StartsOn)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid starts_on date, expected YYYY-MM-DD"})
+		return
+	}
+
+	var client models.Client
+	if err := h.db.Where("id = ? AND trainer_id = ?", req.ClientID, source.TrainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	if err := h.loadContents(&source); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch program"})
+		return
+	}
+
+	// Copies of an assigned program still point at the original template
+	templateID := source.TemplateID
+	if source.IsTemplate() {
+		templateID = &source.ID
+	}
+
+	program := models.Program{
+		TrainerID:   source.TrainerID,
+		ClientID:    &client.ID,
+		TemplateID:  templateID,
+		Name:        source.Name,
+		Description: source.Description,
+		StartsOn:    &startsOn,
+		Weeks:       source.CopyStructure(),
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&program).Error; err != nil {
+			return err
+		}
+		return services.RelinkProgramSessions(tx, client.ID, time.Now())
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign program"})
+		return
+	}
+
+	h.respondProgram(c, http.StatusCreated, program.ID)
+}
+
+// Delete deletes a program. Sessions linked to an assigned program are unlinked.
+func (h *ProgramHandler) Delete(c *gin.Context) {
+	program, ok := h.findProgram(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&program).Error; err != nil {
+			return err
+		}
+		if program.ClientID != nil {
+			return services.RelinkProgramSessions(tx, *program.ClientID, time.Now())
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete program"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Program deleted successfully"})
+}
+
+// findProgram loads the program in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *ProgramHandler) findProgram(c *gin.Context) (models.Program, bool) {
+	var program models.Program
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return program, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid program ID"})
+		return program, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&program).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Program not found"})
+		return program, false
+	}
+
+	return program, true
+}
+
+// loadContents loads a program's weeks, days and exercises in order
+func (h *ProgramHandler) loadContents(program *models.Program) error {
+	return h.db.Preload("Weeks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number ASC")
+	}).Preload("Weeks.Days", func(db *gorm.DB) *gorm.DB {
+		return db.Order("day ASC")
+	}).Preload("Weeks.Days.Exercises", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(program, "id = ?", program.ID).Error
+}
+
+// respondProgram writes a program with its contents and, once assigned, the date of each day
+func (h *ProgramHandler) respondProgram(c *gin.Context, status int, id uuid.UUID) {
+	program := models.Program{ID: id}
+	if err := h.loadContents(&program); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch program"})
+		return
+	}
+	program.FillDates()
+
+	c.JSON(status, program)
+}
+
+// resolveExercises checks that the library exercises prescribed are visible to
+// the trainer and fills in their names where none was given.
+// It writes the error response and returns false otherwise.
+func (h *ProgramHandler) resolveExercises(c *gin.Context, trainerID uuid.UUID, weeks []models.ProgramWeek) bool {
+	var ids []uuid.UUID
+	for _, w := range weeks {
+		for _, d := range w.Days {
+			for _, e := range d.Exercises {
+				if e.ExerciseID != nil {
+					ids = append(ids, *e.ExerciseID)
+				}
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return true
+	}
+
+	names, err := libraryExerciseNames(h.db, trainerID, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exercises"})
+		return false
+	}
+
+	for i := range weeks {
+		for j := range weeks[i].Days {
+			exercises := weeks[i].Days[j].Exercises
+			for k, e := range exercises {
+				if e.ExerciseID == nil {
+					continue
+				}
+				name, found := names[*e.ExerciseID]
+				if !found {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Exercise not found: " + e.ExerciseID.String()})
+					return false
+				}
+				if e.Name == "" {
+					exercises[k].Name = name
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// deleteProgramWeeks removes the weeks, days and exercises of a program
+func deleteProgramWeeks(tx *gorm.DB, programID uuid.UUID) error {
+	weekIDs := func() *gorm.DB {
+		return tx.Model(&models.ProgramWeek{}).Select("id").Where("program_id = ?", programID)
+	}
+
+	if err := tx.Where("program_day_id IN (?)",
+		tx.Model(&models.ProgramDay{}).Select("id").Where("program_week_id IN (?)", weekIDs())).
+		Delete(&models.ProgramExercise{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("program_week_id IN (?)", weekIDs()).Delete(&models.ProgramDay{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("program_id = ?", programID).Delete(&models.ProgramWeek{}).Error
+}
@@ -0,0 +1,524 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportHandler handles revenue and business report HTTP requests
+type ReportHandler struct {
+	db *gorm.DB
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(db *gorm.DB) *ReportHandler {
+	return &ReportHandler{db: db}
+}
+
+// RevenueRow is the money received in a period
+type RevenueRow struct {
+	Period   string  `json:"period"`
+	Currency string  `json:"currency"`
+	Payments float64 `json:"payments"`
+	Refunds  float64 `json:"refunds"`
+	Net      float64 `json:"net"`
+}
+
+// ClientRevenueRow is the money received from a client in a period
+type ClientRevenueRow struct {
+	Period     string    `json:"period"`
+	ClientID   uuid.UUID `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Currency   string    `json:"currency"`
+	Payments   float64   `json:"payments"`
+	Refunds    float64   `json:"refunds"`
+	Net        float64   `json:"net"`
+}
+
+// SessionValueRow is the value of the sessions delivered in a period
+type SessionValueRow struct {
+	Period       string  `json:"period"`
+	Currency     string  `json:"currency"`
+	Sessions     int     `json:"sessions"`
+	Revenue      float64 `json:"revenue"`
+	AveragePrice float64 `json:"average_price"`
+}
+
+// ExpectedRevenueRow is the estimated value of the sessions scheduled in a period
+type ExpectedRevenueRow struct {
+	Period   string  `json:"period"`
+	Currency string  `json:"currency"`
+	Sessions int     `json:"sessions"`
+	Expected float64 `json:"expected"`
+}
+
+// UnearnedRevenueRow is the money received but not yet delivered as sessions at the end of a period
+type UnearnedRevenueRow struct {
+	Period   string  `json:"period"`
+	Currency string  `json:"currency"`
+	Paid     float64 `json:"paid"`
+	Earned   float64 `json:"earned"`
+	Unearned float64 `json:"unearned"`
+}
+
+// reportQuery holds the date range and grouping shared by all reports
+type reportQuery struct {
+	From     time.Time
+	To       time.Time // exclusive
+	Group    string
+	Timezone string
+}
+
+// sessionValueSQL is the price of one session of the package it was taken from
+const sessionValueSQL = "packages.price / NULLIF(packages.session_count, 0)"
+
+// GetRevenue returns payments and refunds per period
+func (h *ReportHandler) GetRevenue(c *gin.Context) {
+	trainerID, query, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	var rows []struct {
+		Period   time.Time
+		Currency string
+		Payments float64
+		Refunds  float64
+	}
+	if err := h.paymentsQuery(trainerID, query).
+		Select(`date_trunc(?, payments.paid_at AT TIME ZONE ?) as period, payments.currency,
+			SUM(CASE WHEN payments.type = ? THEN payments.amount ELSE 0 END) as payments,
+			SUM(CASE WHEN payments.type = ? THEN payments.amount ELSE 0 END) as refunds`,
+			query.Group, query.Timezone, models.PaymentTypePayment, models.PaymentTypeRefund).
+		Group("period, payments.currency").
+		Order("period, payments.currency").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build revenue report"})
+		return
+	}
+
+	report := make([]RevenueRow, 0, len(rows))
+	for _, r := range rows {
+		report = append(report, RevenueRow{
+			Period:   formatPeriod(r.Period),
+			Currency: r.Currency,
+			Payments: services.RoundMoney(r.Payments),
+			Refunds:  services.RoundMoney(r.Refunds),
+			Net:      services.RoundMoney(r.Payments - r.Refunds),
+		})
+	}
+
+	respondReport(c, "revenue", []string{"period", "currency", "payments", "refunds", "net"}, report)
+}
+
+// GetRevenueByClient returns payments and refunds per client and period
+func (h *ReportHandler) GetRevenueByClient(c *gin.Context) {
+	trainerID, query, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	var rows []struct {
+		Period    time.Time
+		ClientID  uuid.UUID
+		FirstName string
+		LastName  string
+		Currency  string
+		Payments  float64
+		Refunds   float64
+	}
+	if err := h.paymentsQuery(trainerID, query).
+		Select(`date_trunc(?, payments.paid_at AT TIME ZONE ?) as period,
+			clients.id as client_id, clients.first_name, clients.last_name, payments.currency,
+			SUM(CASE WHEN payments.type = ? THEN payments.amount ELSE 0 END) as payments,
+			SUM(CASE WHEN payments.type = ? THEN payments.amount ELSE 0 END) as refunds`,
+			query.Group, query.Timezone, models.PaymentTypePayment, models.PaymentTypeRefund).
+		Group("period, clients.id, clients.first_name, clients.last_name, payments.currency").
+		Order("period, clients.first_name, clients.last_name, payments.currency").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build revenue report"})
+		return
+	}
+
+	report := make([]ClientRevenueRow, 0, len(rows))
+	for _, r := range rows {
+		report = append(report, ClientRevenueRow{
+			Period:     formatPeriod(r.Period),
+			ClientID:   r.ClientID,
+			ClientName: r.FirstName + " " + r.LastName,
+			Currency:   r.Currency,
+			Payments:   services.RoundMoney(r.Payments),
+			Refunds:    services.RoundMoney(r.Refunds),
+			Net:        services.RoundMoney(r.Payments - r.Refunds),
+		})
+	}
+
+	respondReport(c, "revenue-by-client",
+		[]string{"period", "client_id", "client_name", "currency", "payments", "refunds", "net"}, report)
+}
+
+// GetSessionValue returns the number and value of delivered sessions per period,
+// with the average price per session
+func (h *ReportHandler) GetSessionValue(c *gin.Context) {
+	trainerID, query, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	var rows []struct {
+		Period   time.Time
+		Currency string
+		Sessions int
+		Revenue  float64
+	}
+	if err := h.deliveredQuery(trainerID).
+		Where("sessions.scheduled_at >= ? AND sessions.scheduled_at < ?", query.From, query.To).
+		Select(`date_trunc(?, sessions.scheduled_at AT TIME ZONE ?) as period, packages.currency,
+			COUNT(*) as sessions, COALESCE(SUM(`+sessionValueSQL+`), 0) as revenue`,
+			query.Group, query.Timezone).
+		Group("period, packages.currency").
+		Order("period, packages.currency").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build session value report"})
+		return
+	}
+
+	report := make([]SessionValueRow, 0, len(rows))
+	for _, r := range rows {
+		row := SessionValueRow{
+			Period:   formatPeriod(r.Period),
+			Currency: r.Currency,
+			Sessions: r.Sessions,
+			Revenue:  services.RoundMoney(r.Revenue),
+		}
+		if r.Sessions > 0 {
+			row.AveragePrice = services.RoundMoney(r.Revenue / float64(r.Sessions))
+		}
+		report = append(report, row)
+	}
+
+	respondReport(c, "session-value", []string{"period", "currency", "sessions", "revenue", "average_price"}, report)
+}
+
+// GetExpectedRevenue estimates the value of scheduled sessions per period, priced
+// at the per-session price of the client's latest package
+func (h *ReportHandler) GetExpectedRevenue(c *gin.Context) {
+	trainerID, query, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	var rows []struct {
+		Period   time.Time
+		Currency string
+		Sessions int
+		Expected float64
+	}
+	if err := h.db.Model(&models.Session{}).
+		Joins("JOIN clients ON clients.id = sessions.client_id").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT price, session_count, currency FROM packages
+			WHERE packages.client_id = sessions.client_id AND packages.status <> ? AND packages.deleted_at IS NULL
+			ORDER BY packages.starts_at DESC LIMIT 1
+		) packages ON true`, models.PackageStatusCancelled).
+		Where("clients.trainer_id = ? AND sessions.status = ?", trainerID, models.SessionStatusScheduled).
+		Where("sessions.scheduled_at >= ? AND sessions.scheduled_at < ?", query.From, query.To).
+		Select(`date_trunc(?, sessions.scheduled_at AT TIME ZONE ?) as period,
+			COALESCE(packages.currency, ?) as currency,
+			COUNT(*) as sessions, COALESCE(SUM(`+sessionValueSQL+`), 0) as expected`,
+			query.Group, query.Timezone, models.DefaultCurrency).
+		Group("period, 2").
+		Order("period, 2").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build expected revenue report"})
+		return
+	}
+
+	report := make([]ExpectedRevenueRow, 0, len(rows))
+	for _, r := range rows {
+		report = append(report, ExpectedRevenueRow{
+			Period:   formatPeriod(r.Period),
+			Currency: r.Currency,
+			Sessions: r.Sessions,
+			Expected: services.RoundMoney(r.Expected),
+		})
+	}
+
+	respondReport(c, "expected-revenue", []string{"period", "currency", "sessions", "expected"}, report)
+}
+
+// GetUnearnedRevenue returns, at the end of each period, the money received that
+// has not yet been delivered as sessions (net payments minus the value of used sessions)
+func (h *ReportHandler) GetUnearnedRevenue(c *gin.Context) {
+	trainerID, query, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+
+	type amount struct {
+		Period   time.Time
+		Currency string
+		Amount   float64
+	}
+
+	// Everything before the range is needed for the opening balance
+	var paid []amount
+	if err := h.db.Model(&models.Payment{}).
+		Joins("JOIN clients ON clients.id = payments.client_id").
+		Where("clients.trainer_id = ? AND payments.paid_at < ?", trainerID, query.To).
+		Select(`date_trunc(?, payments.paid_at AT TIME ZONE ?) as period, payments.currency,
+			SUM(CASE WHEN payments.type = ? THEN -payments.amount ELSE payments.amount END) as amount`,
+			query.Group, query.Timezone, models.PaymentTypeRefund).
+		Group("period, payments.currency").
+		Scan(&paid).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build unearned revenue report"})
+		return
+	}
+
+	var earned []amount
+	if err := h.deliveredQuery(trainerID).
+		Where("sessions.scheduled_at < ?", query.To).
+		Select(`date_trunc(?, sessions.scheduled_at AT TIME ZONE ?) as period, packages.currency,
+			COALESCE(SUM(`+sessionValueSQL+`), 0) as amount`,
+			query.Group, query.Timezone).
+		Group("period, packages.currency").
+		Scan(&earned).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build unearned revenue report"})
+		return
+	}
+
+	type key struct {
+		Period   string
+		Currency string
+	}
+	paidBy := map[key]float64{}
+	earnedBy := map[key]float64{}
+	periods := map[string]bool{}
+	currencies := map[string]bool{}
+	for _, a := range paid {
+		k := key{formatPeriod(a.Period), a.Currency}
+		paidBy[k] += a.Amount
+		periods[k.Period], currencies[k.Currency] = true, true
+	}
+	for _, a := range earned {
+		k := key{formatPeriod(a.Period), a.Currency}
+		earnedBy[k] += a.Amount
+		periods[k.Period], currencies[k.Currency] = true, true
+	}
+
+	// Periods before the range only carry into the opening balance; every period in
+	// the range gets a row, even when nothing was paid or delivered in it
+	sortedPeriods := sortedKeys(periods)
+	rangePeriods := reportPeriods(query.From, query.To, query.Group)
+
+	report := make([]UnearnedRevenueRow, 0)
+	for _, currency := range sortedKeys(currencies) {
+		var totalPaid, totalEarned float64
+		for _, period := range sortedPeriods {
+			if period >= rangePeriods[0] {
+				break
+			}
+			totalPaid += paidBy[key{period, currency}]
+			totalEarned += earnedBy[key{period, currency}]
+		}
+		for _, period := range rangePeriods {
+			k := key{period, currency}
+			totalPaid += paidBy[k]
+			totalEarned += earnedBy[k]
+			report = append(report, UnearnedRevenueRow{
+				Period:   period,
+				Currency: currency,
+				Paid:     services.RoundMoney(totalPaid),
+				Earned:   services.RoundMoney(totalEarned),
+				Unearned: services.RoundMoney(math.Max(0, totalPaid-totalEarned)),
+			})
+		}
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Period < report[j].Period
+	})
+
+	respondReport(c, "unearned-revenue", []string{"period", "currency", "paid", "earned", "unearned"}, report)
+}
+
+// parseQuery reads the date range (from/to as YYYY-MM-DD, both inclusive, in the
+// trainer's timezone) and grouping. It defaults to the last 12 months by month.
+func (h *ReportHandler) parseQuery(c *gin.Context) (uuid.UUID, reportQuery, bool) {
+	var query reportQuery
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return trainerID, query, false
+	}
+
+	var trainer models.Trainer
+	if err := h.db.Select("id", "timezone").First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return trainerID, query, false
+	}
+	loc := trainer.Location()
+	query.Timezone = loc.String()
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	query.From = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -11, 0)
+	query.To = today.AddDate(0, 0, 1)
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return trainerID, query, false
+		}
+		query.From = t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return trainerID, query, false
+		}
+		query.To = t.AddDate(0, 0, 1)
+	}
+	if !query.To.After(query.From) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return trainerID, query, false
+	}
+
+	query.Group = c.DefaultQuery("group", "month")
+	if query.Group != "day" && query.Group != "week" && query.Group != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group must be day, week or month"})
+		return trainerID, query, false
+	}
+
+	return trainerID, query, true
+}
+
+// paymentsQuery selects the trainer's payments within the report range
+func (h *ReportHandler) paymentsQuery(trainerID uuid.UUID, query reportQuery) *gorm.DB {
+	return h.db.Model(&models.Payment{}).
+		Joins("JOIN clients ON clients.id = payments.client_id").
+		Where("clients.trainer_id = ?", trainerID).
+		Where("payments.paid_at >= ? AND payments.paid_at < ?", query.From, query.To)
+}
+
+// deliveredQuery selects the trainer's used sessions together with the package they were taken from
+func (h *ReportHandler) deliveredQuery(trainerID uuid.UUID) *gorm.DB {
+	return h.db.Model(&models.Session{}).
+		Joins("JOIN clients ON clients.id = sessions.client_id").
+		Joins("JOIN packages ON packages.id = sessions.package_id").
+		Where("clients.trainer_id = ? AND sessions.status IN ?", trainerID, models.UsedStatuses())
+}
+
+// csvRecord is implemented by report rows that can be exported as CSV
+type csvRecord interface {
+	csvRecord() []string
+}
+
+// respondReport writes report rows as JSON, or as a CSV download with format=csv
+func respondReport[T csvRecord](c *gin.Context, name string, header []string, rows []T) {
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(header)
+	for _, row := range rows {
+		_ = w.Write(row.csvRecord())
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+func (r RevenueRow) csvRecord() []string {
+	return []string{r.Period, r.Currency, formatAmount(r.Payments), formatAmount(r.Refunds), formatAmount(r.Net)}
+}
+
+func (r ClientRevenueRow) csvRecord() []string {
+	return []string{r.Period, r.ClientID.String(), r.ClientName, r.Currency,
+		formatAmount(r.Payments), formatAmount(r.Refunds), formatAmount(r.Net)}
+}
+
+func (r SessionValueRow) csvRecord() []string {
+	return []string{r.Period, r.Currency, strconv.Itoa(r.Sessions), formatAmount(r.Revenue), formatAmount(r.AveragePrice)}
+}
+
+func (r ExpectedRevenueRow) csvRecord() []string {
+	return []string{r.Period, r.Currency, strconv.Itoa(r.Sessions), formatAmount(r.Expected)}
+}
+
+func (r UnearnedRevenueRow) csvRecord() []string {
+	return []string{r.Period, r.Currency, formatAmount(r.Paid), formatAmount(r.Earned), formatAmount(r.Unearned)}
+}
+
+// formatPeriod formats the start of a report period
+func formatPeriod(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// periodStart returns the start of the day, week (from Monday) or month t falls in,
+// matching date_trunc
+func periodStart(t time.Time, group string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch group {
+	case "week":
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "month":
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// reportPeriods returns every period from..to (exclusive) overlaps, keyed like the report rows
+func reportPeriods(from, to time.Time, group string) []string {
+	var periods []string
+	for start := periodStart(from, group); start.Before(to); {
+		periods = append(periods, formatPeriod(start))
+		switch group {
+		case "week":
+			start = start.AddDate(0, 0, 7)
+		case "month":
+			start = start.AddDate(0, 1, 0)
+		default:
+			start = start.AddDate(0, 0, 1)
+		}
+	}
+	return periods
+}
+
+// formatAmount formats money for CSV export
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// sortedKeys returns the keys of a set in ascending order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
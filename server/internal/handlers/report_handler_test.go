@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondReportCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rows := []RevenueRow{
+		{Period: "2026-01-01", Currency: "TRY", Payments: 1500, Refunds: 200.5, Net: 1299.5},
+		{Period: "2026-02-01", Currency: "EUR", Payments: 90},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/reports/revenue?format=csv", nil)
+	respondReport(c, "revenue", []string{"period", "currency", "payments", "refunds", "net"}, rows)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="revenue.csv"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	want := "period,currency,payments,refunds,net\n" +
+		"2026-01-01,TRY,1500.00,200.50,1299.50\n" +
+		"2026-02-01,EUR,90.00,0.00,0.00\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	got := sortedKeys(map[string]bool{"USD": true, "EUR": true, "TRY": true})
+	if want := []string{"EUR", "TRY", "USD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	// Sunday, late in the evening
+	at := time.Date(2026, time.March, 15, 23, 30, 0, 0, istanbul)
+
+	tests := []struct {
+		group string
+		want  time.Time
+	}{
+		{"day", time.Date(2026, time.March, 15, 0, 0, 0, 0, istanbul)},
+		{"week", time.Date(2026, time.March, 9, 0, 0, 0, 0, istanbul)},
+		{"month", time.Date(2026, time.March, 1, 0, 0, 0, 0, istanbul)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			if got := periodStart(at, tt.group); !got.Equal(tt.want) {
+				t.Errorf("periodStart(%s) = %v, want %v", tt.group, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportPeriods(t *testing.T) {
+	from := time.Date(2026, time.January, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		group string
+		from  time.Time
+		to    time.Time
+		want  []string
+	}{
+		{"day", from, from.AddDate(0, 0, 3), []string{"2026-01-30", "2026-01-31", "2026-02-01"}},
+		{"week", from, to.AddDate(0, 0, -20), []string{"2026-01-26", "2026-02-02", "2026-02-09"}},
+		{"month", from, to, []string{"2026-01-01", "2026-02-01", "2026-03-01"}},
+		{"month", from, from.AddDate(0, 0, 1), []string{"2026-01-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			got := reportPeriods(tt.from, tt.to, tt.group)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reportPeriods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// findConflicts returns the trainer's sessions that overlap the given slot.
+// Sessions whose status frees the slot (e.g. cancelled) are ignored.
+func findConflicts(db *gorm.DB, trainerID uuid.UUID, start time.Time, durationMinutes int, excludeIDs ...uuid.UUID) ([]models.Session, error) {
+	return findSlotConflicts(db, trainerID, []models.TimeSlot{{
+		Start: start,
+		End:   start.Add(time.Duration(durationMinutes) * time.Minute),
+	}}, excludeIDs...)
+}
+
+// findSlotConflicts returns the trainer's sessions that overlap any of the given
+// slots. The sessions of the whole span are loaded in one query and matched here.
+func findSlotConflicts(db *gorm.DB, trainerID uuid.UUID, slots []models.TimeSlot, excludeIDs ...uuid.UUID) ([]models.Session, error) {
+	if len(slots) == 0 {
+		return nil, nil
+	}
+	from, to := slotSpan(slots)
+
+	query := db.Preload("Client").
+		Joins("JOIN clients ON clients.id = sessions.client_id").
+		Where("clients.trainer_id = ?", trainerID).
+		Where("sessions.status NOT IN ?", models.FreeSlotStatuses()).
+		Where("sessions.scheduled_at < ?", to).
+		Where("sessions.scheduled_at + sessions.duration_minutes * INTERVAL '1 minute' > ?", from)
+
+	if len(excludeIDs) > 0 {
+		query = query.Where("sessions.id NOT IN ?", excludeIDs)
+	}
+
+	var sessions []models.Session
+	if err := query.Order("sessions.scheduled_at ASC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	var conflicts []models.Session
+	for _, s := range sessions {
+		for _, slot := range slots {
+			if s.ScheduledAt.Before(slot.End) && s.EndsAt().After(slot.Start) {
+				conflicts = append(conflicts, s)
+				break
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// sessionSlots returns the time slot of each session
+func sessionSlots(sessions []models.Session) []models.TimeSlot {
+	slots := make([]models.TimeSlot, 0, len(sessions))
+	for _, s := range sessions {
+		slots = append(slots, models.TimeSlot{Start: s.ScheduledAt, End: s.EndsAt()})
+	}
+	return slots
+}
+
+// slotSpan returns the earliest start and latest end of non-empty slots
+func slotSpan(slots []models.TimeSlot) (from, to time.Time) {
+	from, to = slots[0].Start, slots[0].End
+	for _, s := range slots[1:] {
+		if s.Start.Before(from) {
+			from = s.Start
+		}
+		if s.End.After(to) {
+			to = s.End
+		}
+	}
+	return from, to
+}
+
+// toConflicts converts overlapping sessions into the API conflict shape
+func toConflicts(sessions []models.Session) []models.SessionConflict {
+	conflicts := make([]models.SessionConflict, 0, len(sessions))
+	seen := make(map[uuid.UUID]bool, len(sessions))
+	for _, s := range sessions {
+		if seen[s.ID] {
+			continue
+		}
+		seen[s.ID] = true
+		conflicts = append(conflicts, models.SessionConflict{
+			SessionID:   s.ID,
+			ClientID:    s.ClientID,
+			ClientName:  s.Client.FirstName + " " + s.Client.LastName,
+			ScheduledAt: s.ScheduledAt,
+			EndsAt:      s.EndsAt(),
+			Status:      s.Status,
+		})
+	}
+	return conflicts
+}
+
+// respondConflict writes the structured 409 response for overlapping sessions
+func respondConflict(c *gin.Context, sessions []models.Session) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error":     "Session overlaps with existing sessions",
+		"conflicts": toConflicts(sessions),
+	})
+}
+
+// workingWindows returns the trainer's working periods between from and to.
+// Date overrides replace the weekly template and time off is removed.
+func workingWindows(db *gorm.DB, trainer models.Trainer, from, to time.Time) ([]models.TimeSlot, error) {
+	loc := trainer.Location()
+	from, to = from.In(loc), to.In(loc)
+	firstDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+
+	var hours []models.WorkingHours
+	if err := db.Where("trainer_id = ?", trainer.ID).Find(&hours).Error; err != nil {
+		return nil, err
+	}
+
+	var overrides []models.AvailabilityOverride
+	if err := db.Where("trainer_id = ? AND date >= ? AND date <= ?", trainer.ID, firstDay.Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&overrides).Error; err != nil {
+		return nil, err
+	}
+	overridesByDate := make(map[string][]models.AvailabilityOverride)
+	for _, o := range overrides {
+		overridesByDate[o.Date] = append(overridesByDate[o.Date], o)
+	}
+
+	var windows []models.TimeSlot
+	for day := firstDay; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if dayOverrides, ok := overridesByDate[day.Format("2006-01-02")]; ok {
+			closed := false
+			for _, o := range dayOverrides {
+				if o.IsUnavailable {
+					closed = true
+					break
+				}
+			}
+			if closed {
+				continue
+			}
+			for _, o := range dayOverrides {
+				if slot, err := services.ClockRange(day, o.StartTime, o.EndTime); err == nil {
+					windows = append(windows, slot)
+				}
+			}
+			continue
+		}
+
+		for _, wh := range hours {
+			if wh.Weekday != int(day.Weekday()) {
+				continue
+			}
+			if slot, err := services.ClockRange(day, wh.StartTime, wh.EndTime); err == nil {
+				windows = append(windows, slot)
+			}
+		}
+	}
+
+	// Clip to the requested range
+	windows = services.SubtractSlots(windows, []models.TimeSlot{
+		{Start: firstDay.AddDate(0, 0, -1), End: from},
+		{Start: to, End: to.AddDate(0, 0, 1)},
+	})
+
+	var timeOff []models.TimeOff
+	if err := db.Where("trainer_id = ? AND starts_at < ? AND ends_at > ?", trainer.ID, to, from).
+		Find(&timeOff).Error; err != nil {
+		return nil, err
+	}
+	blocked := make([]models.TimeSlot, 0, len(timeOff))
+	for _, t := range timeOff {
+		blocked = append(blocked, models.TimeSlot{Start: t.StartsAt, End: t.EndsAt})
+	}
+
+	return services.SubtractSlots(windows, blocked), nil
+}
+
+// busySlots returns the time slots occupied by the trainer's sessions between from and to
+func busySlots(db *gorm.DB, trainerID uuid.UUID, from, to time.Time) ([]models.TimeSlot, error) {
+	var sessions []models.Session
+	if err := db.Joins("JOIN clients ON clients.id = sessions.client_id").
+		Where("clients.trainer_id = ?", trainerID).
+		Where("sessions.status NOT IN ?", models.FreeSlotStatuses()).
+		Where("sessions.scheduled_at < ?", to).
+		Where("sessions.scheduled_at + sessions.duration_minutes * INTERVAL '1 minute' > ?", from).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	busy := make([]models.TimeSlot, 0, len(sessions))
+	for _, s := range sessions {
+		busy = append(busy, models.TimeSlot{Start: s.ScheduledAt, End: s.EndsAt()})
+	}
+	return busy, nil
+}
+
+// freeSlots returns bookable slots of the given duration between from and to
+func freeSlots(db *gorm.DB, trainer models.Trainer, from, to time.Time, duration, step time.Duration) ([]models.TimeSlot, error) {
+	windows, err := workingWindows(db, trainer, from, to)
+	if err != nil {
+		return nil, err
+	}
+	busy, err := busySlots(db, trainer.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return services.SplitSlots(services.SubtractSlots(windows, busy), duration, step), nil
+}
+
+// outsideWorkingHours reports which of the sessions fall outside the trainer's
+// working hours, loading the working periods of their whole span at once.
+// Trainers without a weekly template are never restricted.
+func outsideWorkingHours(db *gorm.DB, trainer models.Trainer, sessions []models.Session) ([]bool, error) {
+	outside := make([]bool, len(sessions))
+	if len(sessions) == 0 {
+		return outside, nil
+	}
+
+	var count int64
+	if err := db.Model(&models.WorkingHours{}).Where("trainer_id = ?", trainer.ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return outside, nil
+	}
+
+	slots := sessionSlots(sessions)
+	from, to := slotSpan(slots)
+	windows, err := workingWindows(db, trainer, from.AddDate(0, 0, -1), to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for i, slot := range slots {
+		outside[i] = !services.CoversSlot(windows, slot)
+	}
+	return outside, nil
+}
+
+// checkWorkingHours applies the trainer's outside-hours policy to the given
+// sessions. It returns false after writing an error response if the sessions
+// must be rejected; otherwise it adds a warning to each session outside hours.
+func checkWorkingHours(c *gin.Context, db *gorm.DB, trainerID uuid.UUID, sessions []models.Session) bool {
+	var trainer models.Trainer
+	if err := db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trainer"})
+		return false
+	}
+
+	isOutside, err := outsideWorkingHours(db, trainer, sessions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check working hours"})
+		return false
+	}
+
+	var outside []time.Time
+	for i := range sessions {
+		if !isOutside[i] {
+			continue
+		}
+		outside = append(outside, sessions[i].ScheduledAt)
+		sessions[i].Warnings = append(sessions[i].Warnings, "Session is outside working hours")
+	}
+
+	if len(outside) > 0 && trainer.OutsideHoursPolicy == models.OutsideHoursReject {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         "Session is outside working hours",
+			"outside_hours": outside,
+		})
+		return false
+	}
+	return true
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"ptmate/internal/models"
+	"ptmate/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -61,6 +63,11 @@ func (h *SessionHandler) GetAll(c *gin.Context) {
 		query = query.Where("sessions.status = ?", status)
 	}
 
+	// Filter to finished sessions still waiting for attendance
+	if c.Query("needs_attendance") == "true" {
+		query = query.Where("sessions.needs_attendance = ?", true)
+	}
+
 	// Filter by date range if provided
 	if from := c.Query("from"); from != "" {
 		query = query.Where("sessions.scheduled_at >= ?", from)
@@ -111,7 +118,35 @@ func (h *SessionHandler) Create(c *gin.Context) {
 		session.DurationMinutes = 60
 	}
 
-	if err := h.db.Create(&session).Error; err != nil {
+	if !req.AllowOverlap {
+		conflicts, err := findConflicts(h.db, trainerID, session.ScheduledAt, session.DurationMinutes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for overlapping sessions"})
+			return
+		}
+		if len(conflicts) > 0 {
+			respondConflict(c, conflicts)
+			return
+		}
+	}
+
+	checked := []models.Session{session}
+	if !checkWorkingHours(c, h.db, trainerID, checked) {
+		return
+	}
+	session = checked[0]
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := services.MatchProgramDay(tx, &session); err != nil {
+			return err
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+		event := models.NewStatusEvent(session, "", models.TrainerActor(trainerID), "")
+		return tx.Create(&event).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
 	}
@@ -145,6 +180,18 @@ func (h *SessionHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	// Include the workout log, if one was recorded
+	if log, err := loadWorkoutLog(h.db, session); err == nil {
+		session.WorkoutLog = &log
+	}
+
+	sessions := []models.Session{session}
+	if err := addSafetyWarnings(h.db, trainerID, sessions, time.Now()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check vitals"})
+		return
+	}
+	session = sessions[0]
+
 	c.JSON(http.StatusOK, session)
 }
 
@@ -176,30 +223,140 @@ func (h *SessionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	// Update only provided fields
+	if !models.IsValidEditScope(req.Scope) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scope"})
+		return
+	}
+	if req.Status != nil && !models.IsValidStatus(*req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	targets, err := h.seriesTargets(session, req.Scope)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series sessions"})
+		return
+	}
+
+	// Cancellations are classified against the trainer's late-cancellation policy
+	lateCancelWindow := 0
+	if req.Status != nil && *req.Status == models.SessionStatusCancelled {
+		if lateCancelWindow, err = cancellationWindow(h.db, trainerID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cancellation policy"})
+			return
+		}
+	}
+	now := time.Now()
+
+	previous := statusesByID(targets)
+
+	// Moving one occurrence shifts the others by the same offset
+	var shift time.Duration
 	if req.ScheduledAt != nil {
-		session.ScheduledAt = *req.ScheduledAt
+		shift = req.ScheduledAt.Sub(session.ScheduledAt)
 	}
-	if req.DurationMinutes != nil {
-		session.DurationMinutes = *req.DurationMinutes
+
+	// Update only provided fields
+	for i := range targets {
+		if req.ScheduledAt != nil {
+			targets[i].ScheduledAt = targets[i].ScheduledAt.Add(shift)
+		}
+		if req.DurationMinutes != nil {
+			targets[i].DurationMinutes = *req.DurationMinutes
+		}
+		if req.Status != nil {
+			status := *req.Status
+			if status == models.SessionStatusCancelled {
+				status = targets[i].ClassifyCancellation(lateCancelWindow, now)
+			}
+			if err := models.ValidateTransition(targets[i].Status, status); err != nil {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			targets[i].Status = status
+		}
+		if req.Notes != nil {
+			targets[i].Notes = *req.Notes
+		}
+		if req.RPE != nil && targets[i].ID == session.ID {
+			if targets[i].Status != models.SessionStatusCompleted {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Session RPE can only be recorded for completed sessions"})
+				return
+			}
+			targets[i].RPE = req.RPE
+		}
 	}
-	if req.Status != nil {
-		if !models.IsValidStatus(*req.Status) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+
+	// Only a changed time slot can introduce a new overlap
+	if !req.AllowOverlap && (req.ScheduledAt != nil || req.DurationMinutes != nil) {
+		excludeIDs := make([]uuid.UUID, 0, len(targets))
+		for _, t := range targets {
+			excludeIDs = append(excludeIDs, t.ID)
+		}
+
+		var blocking []models.Session
+		for _, t := range targets {
+			if models.BlocksSlot(t.Status) {
+				blocking = append(blocking, t)
+			}
+		}
+		conflicts, err := findSlotConflicts(h.db, trainerID, sessionSlots(blocking), excludeIDs...)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for overlapping sessions"})
+			return
+		}
+		if len(conflicts) > 0 {
+			respondConflict(c, conflicts)
 			return
 		}
-		session.Status = *req.Status
 	}
-	if req.Notes != nil {
-		session.Notes = *req.Notes
+
+	if req.ScheduledAt != nil || req.DurationMinutes != nil {
+		if !checkWorkingHours(c, h.db, trainerID, targets) {
+			return
+		}
+	}
+
+	if req.Status != nil {
+		if err := addSafetyWarnings(h.db, trainerID, targets, now); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check vitals"})
+			return
+		}
 	}
 
-	if err := h.db.Save(&session).Error; err != nil {
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for i := range targets {
+			if err := services.AssignPackage(tx, &targets[i]); err != nil {
+				return err
+			}
+			if req.ScheduledAt != nil {
+				if err := services.MatchProgramDay(tx, &targets[i]); err != nil {
+					return err
+				}
+			}
+			if err := tx.Save(&targets[i]).Error; err != nil {
+				return err
+			}
+		}
+		if err := recordStatusChanges(tx, targets, previous, models.TrainerActor(trainerID), req.Reason); err != nil {
+			return err
+		}
+		if completionChanged(targets, previous) {
+			if err := refreshSessionGoals(tx, trainerID, session.ClientID, now); err != nil {
+				return err
+			}
+		}
+		if err := h.splitSeries(tx, trainerID, session, targets, req); err != nil {
+			return err
+		}
+		return h.updateSeriesDefaults(tx, session, req)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
 		return
 	}
 
-	c.JSON(http.StatusOK, session)
+	c.JSON(http.StatusOK, targets[0])
 }
 
 // UpdateStatus updates only the status of a session
@@ -235,16 +392,209 @@ func (h *SessionHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	session.Status = req.Status
+	if !models.IsValidEditScope(req.Scope) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scope"})
+		return
+	}
+
+	targets, err := h.seriesTargets(session, req.Scope)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series sessions"})
+		return
+	}
+
+	// Cancellations are classified against the trainer's late-cancellation policy
+	lateCancelWindow := 0
+	if req.Status == models.SessionStatusCancelled {
+		if lateCancelWindow, err = cancellationWindow(h.db, trainerID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cancellation policy"})
+			return
+		}
+	}
+	now := time.Now()
 
-	if err := h.db.Save(&session).Error; err != nil {
+	previous := statusesByID(targets)
+	for i := range targets {
+		status := req.Status
+		if status == models.SessionStatusCancelled {
+			status = targets[i].ClassifyCancellation(lateCancelWindow, now)
+		}
+		if err := models.ValidateTransition(targets[i].Status, status); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		targets[i].Status = status
+	}
+
+	if err := addSafetyWarnings(h.db, trainerID, targets, now); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check vitals"})
+		return
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for i := range targets {
+			if err := services.AssignPackage(tx, &targets[i]); err != nil {
+				return err
+			}
+			if err := tx.Save(&targets[i]).Error; err != nil {
+				return err
+			}
+		}
+		if err := recordStatusChanges(tx, targets, previous, models.TrainerActor(trainerID), req.Reason); err != nil {
+			return err
+		}
+		if completionChanged(targets, previous) {
+			return refreshSessionGoals(tx, trainerID, session.ClientID, now)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session status"})
 		return
 	}
 
+	c.JSON(http.StatusOK, targets[0])
+}
+
+// Approve confirms a pending booking request
+func (h *SessionHandler) Approve(c *gin.Context) {
+	h.resolveBooking(c, models.SessionStatusScheduled)
+}
+
+// Reject declines a pending booking request
+func (h *SessionHandler) Reject(c *gin.Context) {
+	h.resolveBooking(c, models.SessionStatusRejected)
+}
+
+// resolveBooking moves a pending session to the given status
+func (h *SessionHandler) resolveBooking(c *gin.Context, status models.SessionStatus) {
+	trainerID, ok := h.getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var session models.Session
+	if err := h.db.Joins("JOIN clients ON clients.id = sessions.client_id").
+		Where("sessions.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if session.Status != models.SessionStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Session is not a pending booking request"})
+		return
+	}
+	if err := models.ValidateTransition(session.Status, status); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req models.RejectBookingRequest
+	if status == models.SessionStatusRejected {
+		// Reason is optional, so an empty body is fine
+		_ = c.ShouldBindJSON(&req)
+	}
+
+	previous := session.Status
+	session.Status = status
+	if req.Reason != "" {
+		if session.Notes != "" {
+			session.Notes += "\n"
+		}
+		session.Notes += "Rejected: " + req.Reason
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&session).Error; err != nil {
+			return err
+		}
+		event := models.NewStatusEvent(session, previous, models.TrainerActor(trainerID), req.Reason)
+		return tx.Create(&event).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update booking request"})
+		return
+	}
+
 	c.JSON(http.StatusOK, session)
 }
 
+// CheckConflicts reports overlapping sessions for a slot without saving anything
+func (h *SessionHandler) CheckConflicts(c *gin.Context) {
+	trainerID, ok := h.getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.ConflictCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Default duration to 60 minutes
+	if req.DurationMinutes == 0 {
+		req.DurationMinutes = 60
+	}
+
+	var excludeIDs []uuid.UUID
+	if req.ExcludeSessionID != nil {
+		excludeIDs = append(excludeIDs, *req.ExcludeSessionID)
+	}
+
+	conflicts, err := findConflicts(h.db, trainerID, req.ScheduledAt, req.DurationMinutes, excludeIDs...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for overlapping sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ConflictCheckResponse{
+		HasConflicts: len(conflicts) > 0,
+		Conflicts:    toConflicts(conflicts),
+	})
+}
+
+// GetTimeline returns a session's status history, oldest first
+func (h *SessionHandler) GetTimeline(c *gin.Context) {
+	trainerID, ok := h.getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	// Deleted sessions keep their history for billing disputes
+	var session models.Session
+	if err := h.db.Unscoped().Joins("JOIN clients ON clients.id = sessions.client_id").
+		Where("sessions.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	var events []models.SessionStatusEvent
+	if err := h.db.Where("session_id = ?", session.ID).Order("created_at ASC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session timeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
 // Delete soft deletes a session
 func (h *SessionHandler) Delete(c *gin.Context) {
 	trainerID, ok := h.getTrainerID(c)
@@ -268,10 +618,227 @@ func (h *SessionHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.Delete(&session).Error; err != nil {
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&session).Error; err != nil {
+			return err
+		}
+		from := session.Status
+		session.Status = models.SessionStatusDeleted
+		event := models.NewStatusEvent(session, from, models.TrainerActor(trainerID), c.Query("reason"))
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		if from == models.SessionStatusCompleted {
+			return refreshSessionGoals(tx, trainerID, session.ClientID, time.Now())
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Session deleted successfully"})
 }
+
+// seriesTargets returns the sessions an edit applies to. The session itself is
+// always first; for series scopes the remaining scheduled occurrences follow.
+func (h *SessionHandler) seriesTargets(session models.Session, scope models.EditScope) ([]models.Session, error) {
+	targets := []models.Session{session}
+	if session.SeriesID == nil || scope == "" || scope == models.EditScopeThis {
+		return targets, nil
+	}
+
+	query := h.db.Where("series_id = ? AND id <> ? AND status = ?", *session.SeriesID, session.ID, models.SessionStatusScheduled)
+	if scope == models.EditScopeFollowing {
+		query = query.Where("scheduled_at > ?", session.ScheduledAt)
+	}
+
+	var siblings []models.Session
+	if err := query.Order("scheduled_at ASC").Find(&siblings).Error; err != nil {
+		return nil, err
+	}
+
+	return append(targets, siblings...), nil
+}
+
+// updateSeriesDefaults keeps the series definition in line with whole-series edits
+func (h *SessionHandler) updateSeriesDefaults(tx *gorm.DB, session models.Session, req models.UpdateSessionRequest) error {
+	if session.SeriesID == nil || req.Scope != models.EditScopeAll {
+		return nil
+	}
+
+	updates := map[string]interface{}{}
+	if req.DurationMinutes != nil {
+		updates["duration_minutes"] = *req.DurationMinutes
+	}
+	if req.Notes != nil {
+		updates["notes"] = *req.Notes
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return tx.Model(&models.SessionSeries{}).Where("id = ?", *session.SeriesID).Updates(updates).Error
+}
+
+// splitSeries applies a "this and following" edit to the series definition. The old
+// series' rule ends just before the edited occurrence, and the edited occurrences
+// move to a new series that starts at the edited occurrence with the edited values.
+// targets are the edited occurrences with session, the occurrence the edit was made on, first.
+func (h *SessionHandler) splitSeries(tx *gorm.DB, trainerID uuid.UUID, session models.Session, targets []models.Session, req models.UpdateSessionRequest) error {
+	if session.SeriesID == nil || req.Scope != models.EditScopeFollowing {
+		return nil
+	}
+	if req.ScheduledAt == nil && req.DurationMinutes == nil && req.Notes == nil {
+		return nil
+	}
+
+	var series models.SessionSeries
+	if err := tx.First(&series, "id = ?", *session.SeriesID).Error; err != nil {
+		return err
+	}
+	var trainer models.Trainer
+	if err := tx.Select("timezone").First(&trainer, "id = ?", trainerID).Error; err != nil {
+		return err
+	}
+	loc := trainer.Location()
+	rule, err := services.ParseRRule(series.RRule, loc)
+	if err != nil {
+		return err
+	}
+
+	edited := targets[0]
+	before, after := rule.SplitAt(series.StartsAt.In(loc), session.ScheduledAt)
+	after.ShiftDays(services.DaysBetween(session.ScheduledAt.In(loc), edited.ScheduledAt.In(loc)))
+	shift := edited.ScheduledAt.Sub(session.ScheduledAt)
+
+	var earlier, following []time.Time
+	for _, ex := range series.ExDates {
+		if ex.Before(session.ScheduledAt) {
+			earlier = append(earlier, ex)
+		} else {
+			following = append(following, ex.Add(shift))
+		}
+	}
+
+	next := models.SessionSeries{
+		ClientID:        series.ClientID,
+		StartsAt:        edited.ScheduledAt,
+		DurationMinutes: edited.DurationMinutes,
+		RRule:           after.String(),
+		ExDates:         following,
+		Notes:           series.Notes,
+	}
+	if req.Notes != nil {
+		next.Notes = *req.Notes
+	}
+
+	// The edited occurrence is the first one, so nothing is left to split off
+	if !session.ScheduledAt.After(series.StartsAt) {
+		series.StartsAt = next.StartsAt
+		series.DurationMinutes = next.DurationMinutes
+		series.RRule = next.RRule
+		series.ExDates = next.ExDates
+		series.Notes = next.Notes
+		return tx.Save(&series).Error
+	}
+
+	if err := tx.Create(&next).Error; err != nil {
+		return err
+	}
+	series.RRule = before.String()
+	series.ExDates = earlier
+	if err := tx.Save(&series).Error; err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(targets))
+	for i := range targets {
+		targets[i].SeriesID = &next.ID
+		ids = append(ids, targets[i].ID)
+	}
+	return tx.Model(&models.Session{}).
+		Where("series_id = ? AND (id IN ? OR scheduled_at >= ?)", series.ID, ids, session.ScheduledAt).
+		Update("series_id", next.ID).Error
+}
+
+// cancellationWindow returns the trainer's late-cancellation window in hours
+func cancellationWindow(db *gorm.DB, trainerID uuid.UUID) (int, error) {
+	var trainer models.Trainer
+	if err := db.Select("late_cancel_window_hours").First(&trainer, "id = ?", trainerID).Error; err != nil {
+		return 0, err
+	}
+	return trainer.LateCancelWindowHours, nil
+}
+
+// refreshSessionGoals re-evaluates a client's goals after sessions were completed
+// or stopped being completed, so that session goals can be achieved or undone
+func refreshSessionGoals(tx *gorm.DB, trainerID, clientID uuid.UUID, now time.Time) error {
+	var trainer models.Trainer
+	if err := tx.Select("timezone").First(&trainer, "id = ?", trainerID).Error; err != nil {
+		return err
+	}
+	return services.RefreshGoals(tx, clientID, trainer.Location(), now)
+}
+
+// addSafetyWarnings warns on each scheduled or completed session whose day has
+// started by now about the client's unsafe vitals on that day, so that the trainer
+// sees them when opening the session to start it and when changing its status
+func addSafetyWarnings(db *gorm.DB, trainerID uuid.UUID, sessions []models.Session, now time.Time) error {
+	var trainer models.Trainer
+	if err := db.Select("timezone").First(&trainer, "id = ?", trainerID).Error; err != nil {
+		return err
+	}
+	loc := trainer.Location()
+	local := now.In(loc)
+	tomorrow := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+	for i := range sessions {
+		if sessions[i].Status != models.SessionStatusScheduled && sessions[i].Status != models.SessionStatusCompleted {
+			continue
+		}
+		if !sessions[i].ScheduledAt.Before(tomorrow) {
+			continue
+		}
+		_, warnings, err := services.SafetyCheck(db, sessions[i].ClientID, sessions[i].ScheduledAt, loc)
+		if err != nil {
+			return err
+		}
+		sessions[i].Warnings = append(sessions[i].Warnings, warnings...)
+	}
+	return nil
+}
+
+// statusesByID snapshots the current status of each session before an edit
+func statusesByID(sessions []models.Session) map[uuid.UUID]models.SessionStatus {
+	statuses := make(map[uuid.UUID]models.SessionStatus, len(sessions))
+	for _, s := range sessions {
+		statuses[s.ID] = s.Status
+	}
+	return statuses
+}
+
+// completionChanged reports whether any session moved to or from completed
+func completionChanged(sessions []models.Session, previous map[uuid.UUID]models.SessionStatus) bool {
+	for _, s := range sessions {
+		if (previous[s.ID] == models.SessionStatusCompleted) != (s.Status == models.SessionStatusCompleted) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordStatusChanges writes a status event for every session whose status changed
+func recordStatusChanges(tx *gorm.DB, sessions []models.Session, previous map[uuid.UUID]models.SessionStatus, actor models.StatusActor, reason string) error {
+	var events []models.SessionStatusEvent
+	for _, s := range sessions {
+		if previous[s.ID] == s.Status {
+			continue
+		}
+		events = append(events, models.NewStatusEvent(s, previous[s.ID], actor, reason))
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	return tx.Create(&events).Error
+}
@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SessionSeriesHandler handles recurring session series HTTP requests
+type SessionSeriesHandler struct {
+	db *gorm.DB
+}
+
+// NewSessionSeriesHandler creates a new SessionSeriesHandler
+func NewSessionSeriesHandler(db *gorm.DB) *SessionSeriesHandler {
+	return &SessionSeriesHandler{db: db}
+}
+
+// GetAll returns all session series for trainer's clients
+func (h *SessionSeriesHandler) GetAll(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	query := h.db.Preload("Client").
+		Joins("JOIN clients ON clients.id = session_series.client_id").
+		Where("clients.trainer_id = ?", trainerID)
+
+	// Filter by client_id if provided
+	if clientID := c.Query("client_id"); clientID != "" {
+		id, err := uuid.Parse(clientID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+			return
+		}
+		query = query.Where("session_series.client_id = ?", id)
+	}
+
+	var series []models.SessionSeries
+	if err := query.Order("session_series.starts_at ASC").Find(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Create creates a new session series and expands it into sessions
+func (h *SessionSeriesHandler) Create(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var req models.CreateSessionSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Verify client exists and belongs to trainer
+	var client models.Client
+	if err := h.db.Where("id = ? AND trainer_id = ?", req.ClientID, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+	// Expand in the trainer's timezone rather than the request's fixed offset,
+	// so sessions after a daylight saving change keep their local time
+	loc := trainer.Location()
+	startsAt := req.StartsAt.In(loc)
+
+	rule, err := services.ParseRRule(req.RRule, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rrule: " + err.Error()})
+		return
+	}
+
+	capacity, err := packageCapacity(h.db, client)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate remaining sessions"})
+		return
+	}
+	if capacity <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Client has no remaining sessions in package"})
+		return
+	}
+
+	// Expand one extra occurrence so we can tell whether the package cut the series short
+	occurrences := rule.Expand(startsAt, req.ExDates, capacity+1)
+	truncated := len(occurrences) > capacity
+	if truncated {
+		occurrences = occurrences[:capacity]
+	}
+	if len(occurrences) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Rule does not produce any sessions"})
+		return
+	}
+
+	// Default duration to 60 minutes
+	durationMinutes := req.DurationMinutes
+	if durationMinutes == 0 {
+		durationMinutes = 60
+	}
+
+	sessions := make([]models.Session, 0, len(occurrences))
+	for _, at := range occurrences {
+		sessions = append(sessions, models.Session{
+			ClientID:        req.ClientID,
+			ScheduledAt:     at,
+			DurationMinutes: durationMinutes,
+			Status:          models.SessionStatusScheduled,
+			Notes:           req.Notes,
+		})
+	}
+
+	if !req.AllowOverlap {
+		conflicts, err := findSlotConflicts(h.db, trainerID, sessionSlots(sessions))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for overlapping sessions"})
+			return
+		}
+		if len(conflicts) > 0 {
+			respondConflict(c, conflicts)
+			return
+		}
+	}
+
+	if !checkWorkingHours(c, h.db, trainerID, sessions) {
+		return
+	}
+	var warnings []string
+	for _, s := range sessions {
+		for _, w := range s.Warnings {
+			warnings = append(warnings, s.ScheduledAt.Format(time.RFC3339)+": "+w)
+		}
+	}
+
+	series := models.SessionSeries{
+		ClientID:        req.ClientID,
+		StartsAt:        startsAt,
+		DurationMinutes: durationMinutes,
+		RRule:           rule.String(),
+		ExDates:         req.ExDates,
+		Notes:           req.Notes,
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&series).Error; err != nil {
+			return err
+		}
+		for i := range sessions {
+			sessions[i].SeriesID = &series.ID
+		}
+		if err := tx.Create(&sessions).Error; err != nil {
+			return err
+		}
+		if err := services.RelinkProgramSessions(tx, client.ID, startsAt); err != nil {
+			return err
+		}
+		return recordStatusChanges(tx, sessions, nil, models.TrainerActor(trainerID), "")
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session series"})
+		return
+	}
+
+	// Load sessions for response
+	h.db.Preload("Sessions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("scheduled_at ASC")
+	}).First(&series, "id = ?", series.ID)
+
+	c.JSON(http.StatusCreated, models.SessionSeriesResponse{
+		SessionSeries: series,
+		Truncated:     truncated,
+		Warnings:      warnings,
+	})
+}
+
+// GetByID returns a session series with its sessions
+func (h *SessionSeriesHandler) GetByID(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var series models.SessionSeries
+	if err := h.db.Preload("Client").
+		Preload("Sessions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scheduled_at ASC")
+		}).
+		Joins("JOIN clients ON clients.id = session_series.client_id").
+		Where("session_series.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&series).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session series not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Delete cancels the series' upcoming scheduled sessions and removes the series.
+// Cancellations go through the late-cancellation policy like single sessions;
+// past sessions are left for attendance marking.
+func (h *SessionSeriesHandler) Delete(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var series models.SessionSeries
+	if err := h.db.Joins("JOIN clients ON clients.id = session_series.client_id").
+		Where("session_series.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&series).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session series not found"})
+		return
+	}
+
+	lateCancelWindow, err := cancellationWindow(h.db, trainerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch cancellation policy"})
+		return
+	}
+	now := time.Now()
+
+	var upcoming []models.Session
+	if err := h.db.Where("series_id = ? AND status = ? AND scheduled_at > ?", series.ID, models.SessionStatusScheduled, now).
+		Find(&upcoming).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series sessions"})
+		return
+	}
+
+	previous := statusesByID(upcoming)
+	for i := range upcoming {
+		status := upcoming[i].ClassifyCancellation(lateCancelWindow, now)
+		if err := models.ValidateTransition(upcoming[i].Status, status); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		upcoming[i].Status = status
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for i := range upcoming {
+			if err := services.AssignPackage(tx, &upcoming[i]); err != nil {
+				return err
+			}
+			if err := tx.Save(&upcoming[i]).Error; err != nil {
+				return err
+			}
+		}
+		if err := recordStatusChanges(tx, upcoming, previous, models.TrainerActor(trainerID), "Series deleted"); err != nil {
+			return err
+		}
+		return tx.Delete(&series).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Session series deleted successfully",
+		"cancelled_sessions": len(upcoming),
+	})
+}
+
+// packageCapacity returns how many more sessions can be booked for a client
+// before their packages run out. Pending and scheduled sessions count as already booked.
+func packageCapacity(db *gorm.DB, client models.Client) (int, error) {
+	balance, err := services.LoadPackageBalance(db, client.ID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	var upcoming int64
+	if err := db.Model(&models.Session{}).
+		Where("client_id = ? AND status IN ?", client.ID, []models.SessionStatus{
+			models.SessionStatusPending,
+			models.SessionStatusScheduled,
+		}).
+		Count(&upcoming).Error; err != nil {
+		return 0, err
+	}
+	return balance.Remaining - int(upcoming), nil
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SettingsHandler handles trainer settings HTTP requests
+type SettingsHandler struct {
+	db *gorm.DB
+}
+
+// NewSettingsHandler creates a new SettingsHandler
+func NewSettingsHandler(db *gorm.DB) *SettingsHandler {
+	return &SettingsHandler{db: db}
+}
+
+// Get returns the authenticated trainer with their settings
+func (h *SettingsHandler) Get(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, trainer)
+}
+
+// Update updates the authenticated trainer's settings
+func (h *SettingsHandler) Update(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
+	var req models.UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update only provided fields
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+		trainer.Timezone = *req.Timezone
+	}
+	if req.OutsideHoursPolicy != nil {
+		if *req.OutsideHoursPolicy != models.OutsideHoursWarn && *req.OutsideHoursPolicy != models.OutsideHoursReject {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid outside_hours_policy"})
+			return
+		}
+		trainer.OutsideHoursPolicy = *req.OutsideHoursPolicy
+	}
+
+	if req.LateCancelWindowHours != nil {
+		if *req.LateCancelWindowHours < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "late_cancel_window_hours cannot be negative"})
+			return
+		}
+		trainer.LateCancelWindowHours = *req.LateCancelWindowHours
+	}
+	if req.NoShowPolicy != nil {
+		switch *req.NoShowPolicy {
+		case models.NoShowPolicyMark, models.NoShowPolicyFlag, models.NoShowPolicyOff:
+			trainer.NoShowPolicy = *req.NoShowPolicy
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid no_show_policy"})
+			return
+		}
+	}
+
+	if req.BookingEnabled != nil {
+		trainer.BookingEnabled = *req.BookingEnabled
+	}
+	if req.BookingDurationMinutes != nil {
+		if *req.BookingDurationMinutes <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "booking_duration_minutes must be positive"})
+			return
+		}
+		trainer.BookingDurationMinutes = *req.BookingDurationMinutes
+	}
+	if req.BookingMinNoticeHours != nil {
+		if *req.BookingMinNoticeHours < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "booking_min_notice_hours cannot be negative"})
+			return
+		}
+		trainer.BookingMinNoticeHours = *req.BookingMinNoticeHours
+	}
+	if req.BookingMaxAdvanceDays != nil {
+		if *req.BookingMaxAdvanceDays <= 0 || *req.BookingMaxAdvanceDays > maxSlotSearchDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "booking_max_advance_days must be between 1 and 62"})
+			return
+		}
+		trainer.BookingMaxAdvanceDays = *req.BookingMaxAdvanceDays
+	}
+
+	if req.BusinessName != nil {
+		trainer.BusinessName = *req.BusinessName
+	}
+	if req.BusinessAddress != nil {
+		trainer.BusinessAddress = *req.BusinessAddress
+	}
+	if req.BusinessPhone != nil {
+		trainer.BusinessPhone = *req.BusinessPhone
+	}
+	if req.TaxOffice != nil {
+		trainer.TaxOffice = *req.TaxOffice
+	}
+	if req.TaxNumber != nil {
+		trainer.TaxNumber = *req.TaxNumber
+	}
+
+	if req.UnitSystem != nil {
+		if !models.IsValidUnitSystem(*req.UnitSystem) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unit_system must be metric or imperial"})
+			return
+		}
+		trainer.UnitSystem = *req.UnitSystem
+	}
+
+	if req.AtRiskMinAttendanceRate != nil {
+		if *req.AtRiskMinAttendanceRate < 0 || *req.AtRiskMinAttendanceRate > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at_risk_min_attendance_rate must be between 0 and 100"})
+			return
+		}
+		trainer.AtRiskMinAttendanceRate = *req.AtRiskMinAttendanceRate
+	}
+	if req.AtRiskMaxNoShowRate != nil {
+		if *req.AtRiskMaxNoShowRate < 0 || *req.AtRiskMaxNoShowRate > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at_risk_max_no_show_rate must be between 0 and 100"})
+			return
+		}
+		trainer.AtRiskMaxNoShowRate = *req.AtRiskMaxNoShowRate
+	}
+	if req.AtRiskInactiveDays != nil {
+		if *req.AtRiskInactiveDays < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at_risk_inactive_days cannot be negative"})
+			return
+		}
+		trainer.AtRiskInactiveDays = *req.AtRiskInactiveDays
+	}
+	if req.AtRiskMinSessionsPerWeek != nil {
+		if *req.AtRiskMinSessionsPerWeek < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at_risk_min_sessions_per_week cannot be negative"})
+			return
+		}
+		trainer.AtRiskMinSessionsPerWeek = *req.AtRiskMinSessionsPerWeek
+	}
+
+	// Enabling public booking for the first time issues a booking link token
+	if trainer.BookingEnabled && trainer.BookingToken == nil {
+		token, err := generateBookingToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate booking token"})
+			return
+		}
+		trainer.BookingToken = &token
+	}
+
+	if err := h.db.Save(&trainer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, trainer)
+}
+
+// RegenerateBookingToken issues a new public booking token, invalidating the old link
+func (h *SettingsHandler) RegenerateBookingToken(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	token, err := generateBookingToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate booking token"})
+		return
+	}
+
+	if err := h.db.Model(&models.Trainer{}).Where("id = ?", trainerID).Update("booking_token", token).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update booking token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"booking_token": token})
+}
+
+// generateBookingToken returns a random URL-safe token for the public booking link
+func generateBookingToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
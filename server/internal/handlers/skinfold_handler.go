@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SkinfoldHandler handles skinfold caliper measurement HTTP requests
+type SkinfoldHandler struct {
+	db *gorm.DB
+}
+
+// NewSkinfoldHandler creates a new SkinfoldHandler
+func NewSkinfoldHandler(db *gorm.DB) *SkinfoldHandler {
+	return &SkinfoldHandler{db: db}
+}
+
+// GetByClient returns a client's skinfold measurements, newest first
+func (h *SkinfoldHandler) GetByClient(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var skinfolds []models.Skinfold
+	if err := h.db.Where("client_id = ?", client.ID).Order("measured_at DESC").Find(&skinfolds).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skinfolds"})
+		return
+	}
+
+	c.JSON(http.StatusOK, skinfolds)
+}
+
+// Create records a skinfold measurement and calculates its body fat
+func (h *SkinfoldHandler) Create(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveSkinfoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	skinfold := models.Skinfold{ClientID: client.ID}
+	if !applySkinfold(c, &skinfold, req, client) {
+		return
+	}
+
+	if err := h.db.Create(&skinfold).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create skinfold"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, skinfold)
+}
+
+// GetByID returns a skinfold measurement
+func (h *SkinfoldHandler) GetByID(c *gin.Context) {
+	skinfold, ok := h.findSkinfold(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, skinfold)
+}
+
+// Update replaces a skinfold measurement and recalculates its body fat
+// with the client's current sex
+func (h *SkinfoldHandler) Update(c *gin.Context) {
+	skinfold, ok := h.findSkinfold(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveSkinfoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var client models.Client
+	if err := h.db.First(&client, "id = ?", skinfold.ClientID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return
+	}
+
+	measuredAt := skinfold.MeasuredAt
+	if !applySkinfold(c, &skinfold, req, client) {
+		return
+	}
+	if req.MeasuredAt.IsZero() {
+		skinfold.MeasuredAt = measuredAt
+	}
+
+	if err := h.db.Save(&skinfold).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update skinfold"})
+		return
+	}
+
+	c.JSON(http.StatusOK, skinfold)
+}
+
+// Delete soft deletes a skinfold measurement
+func (h *SkinfoldHandler) Delete(c *gin.Context) {
+	skinfold, ok := h.findSkinfold(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(&skinfold).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete skinfold"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Skinfold deleted successfully"})
+}
+
+// findClient loads the client in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *SkinfoldHandler) findClient(c *gin.Context) (models.Client, bool) {
+	var client models.Client
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return client, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return client, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return client, false
+	}
+
+	return client, true
+}
+
+// findSkinfold loads the skinfold measurement in the URL if its client belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *SkinfoldHandler) findSkinfold(c *gin.Context) (models.Skinfold, bool) {
+	var skinfold models.Skinfold
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return skinfold, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skinfold ID"})
+		return skinfold, false
+	}
+
+	if err := h.db.Joins("JOIN clients ON clients.id = skinfolds.client_id").
+		Where("skinfolds.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&skinfold).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Skinfold not found"})
+		return skinfold, false
+	}
+
+	return skinfold, true
+}
+
+// applySkinfold copies the request onto the record and calculates it with the
+// client's sex and, unless given, age.
+// It writes the error response and returns false if the record cannot be calculated.
+func applySkinfold(c *gin.Context, skinfold *models.Skinfold, req models.SaveSkinfoldRequest, client models.Client) bool {
+	skinfold.Protocol = req.Protocol
+	skinfold.ChestMm = req.ChestMm
+	skinfold.MidaxillaryMm = req.MidaxillaryMm
+	skinfold.TricepsMm = req.TricepsMm
+	skinfold.SubscapularMm = req.SubscapularMm
+	skinfold.AbdominalMm = req.AbdominalMm
+	skinfold.SuprailiacMm = req.SuprailiacMm
+	skinfold.ThighMm = req.ThighMm
+	skinfold.Notes = req.Notes
+	skinfold.MeasuredAt = req.MeasuredAt
+	skinfold.Sex = client.Sex
+
+	skinfold.Age = 0
+	if req.Age != nil {
+		skinfold.Age = *req.Age
+	} else if client.Age != nil {
+		skinfold.Age = *client.Age
+	}
+
+	// Default to current time if not provided
+	if skinfold.MeasuredAt.IsZero() {
+		skinfold.MeasuredAt = time.Now()
+	}
+
+	if problem := skinfold.Calculate(); problem != "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": problem})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StrengthRecordHandler handles strength test HTTP requests
+type StrengthRecordHandler struct {
+	db *gorm.DB
+}
+
+// NewStrengthRecordHandler creates a new StrengthRecordHandler
+func NewStrengthRecordHandler(db *gorm.DB) *StrengthRecordHandler {
+	return &StrengthRecordHandler{db: db}
+}
+
+// GetByClient returns a client's strength tests, newest first, optionally for one lift
+func (h *StrengthRecordHandler) GetByClient(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	query := h.db.Where("client_id = ?", client.ID)
+	if lift := strings.TrimSpace(c.Query("lift")); lift != "" {
+		query = query.Where("LOWER(lift) = ?", strings.ToLower(lift))
+	}
+
+	var records []models.StrengthRecord
+	if err := query.Order("tested_at DESC, created_at DESC").Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch strength records"})
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, client.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+	for i := range records {
+		records[i].Present(system)
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// Create records a strength test and flags it if it is a new personal best
+func (h *StrengthRecordHandler) Create(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateStrengthRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	record := models.StrengthRecord{
+		ClientID:   client.ID,
+		ExerciseID: req.ExerciseID,
+		Lift:       strings.TrimSpace(req.Lift),
+		LoadKg:     req.LoadKg,
+		Reps:       req.Reps,
+		TestedAt:   req.TestedAt,
+		Notes:      req.Notes,
+	}
+
+	system, err := clientUnitSystem(h.db, client.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+	if problem := record.ApplyValues(orUnitSystem(req.UnitSystem, system), req.Values); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+	if record.LoadKg <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "load_kg or values.load is required"})
+		return
+	}
+
+	if record.ExerciseID != nil {
+		names, err := libraryExerciseNames(h.db, client.TrainerID, []uuid.UUID{*record.ExerciseID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exercises"})
+			return
+		}
+		name, found := names[*record.ExerciseID]
+		if !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Exercise not found: " + record.ExerciseID.String()})
+			return
+		}
+		if record.Lift == "" {
+			record.Lift = name
+		}
+	}
+
+	// Default to current time if not provided
+	if record.TestedAt.IsZero() {
+		record.TestedAt = time.Now()
+	}
+	record.Estimate()
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		return services.RecalculatePersonalBests(tx, client.ID, record.Lift)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create strength record"})
+		return
+	}
+
+	h.respondRecord(c, http.StatusCreated, record.ID)
+}
+
+// GetProgress returns the estimated one-rep max over time for each lift,
+// or only for the lift given in the query
+func (h *StrengthRecordHandler) GetProgress(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	progress, err := services.StrengthProgression(h.db, client.ID, strings.TrimSpace(c.Query("lift")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch strength progress"})
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, client.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+	for i := range progress {
+		progress[i].Present(system)
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// Update updates a strength test and recalculates its estimates and personal bests
+func (h *StrengthRecordHandler) Update(c *gin.Context) {
+	record, ok := h.findRecord(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateStrengthRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	previousLift := record.Lift
+
+	// Update only provided fields
+	if req.Lift != nil {
+		if strings.TrimSpace(*req.Lift) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Lift cannot be empty"})
+			return
+		}
+		record.Lift = strings.TrimSpace(*req.Lift)
+	}
+	if req.LoadKg != nil {
+		record.LoadKg = *req.LoadKg
+	}
+	if len(req.Values) > 0 {
+		system, err := clientUnitSystem(h.db, record.ClientID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+			return
+		}
+		if problem := record.ApplyValues(orUnitSystem(req.UnitSystem, system), req.Values); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+	}
+	if req.Reps != nil {
+		record.Reps = *req.Reps
+	}
+	if req.TestedAt != nil {
+		record.TestedAt = *req.TestedAt
+	}
+	if req.Notes != nil {
+		record.Notes = *req.Notes
+	}
+	record.Estimate()
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&record).Error; err != nil {
+			return err
+		}
+		if err := services.RecalculatePersonalBests(tx, record.ClientID, record.Lift); err != nil {
+			return err
+		}
+		if !strings.EqualFold(previousLift, record.Lift) {
+			return services.RecalculatePersonalBests(tx, record.ClientID, previousLift)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update strength record"})
+		return
+	}
+
+	h.respondRecord(c, http.StatusOK, record.ID)
+}
+
+// Delete soft deletes a strength test; the next best test of the lift becomes the personal best
+func (h *StrengthRecordHandler) Delete(c *gin.Context) {
+	record, ok := h.findRecord(c)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&record).Error; err != nil {
+			return err
+		}
+		return services.RecalculatePersonalBests(tx, record.ClientID, record.Lift)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete strength record"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Strength record deleted successfully"})
+}
+
+// findClient loads the client in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *StrengthRecordHandler) findClient(c *gin.Context) (models.Client, bool) {
+	var client models.Client
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return client, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return client, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return client, false
+	}
+
+	return client, true
+}
+
+// findRecord loads the strength record in the URL if its client belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *StrengthRecordHandler) findRecord(c *gin.Context) (models.StrengthRecord, bool) {
+	var record models.StrengthRecord
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return record, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid strength record ID"})
+		return record, false
+	}
+
+	if err := h.db.Joins("JOIN clients ON clients.id = strength_records.client_id").
+		Where("strength_records.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&record).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Strength record not found"})
+		return record, false
+	}
+
+	return record, true
+}
+
+// respondRecord writes a strength record as stored, with its personal best flag up
+// to date and its values in the client's unit system
+func (h *StrengthRecordHandler) respondRecord(c *gin.Context, status int, id uuid.UUID) {
+	var record models.StrengthRecord
+	if err := h.db.First(&record, "id = ?", id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch strength record"})
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, record.ClientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+	record.Present(system)
+
+	c.JSON(status, record)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrainingLoadHandler handles session-RPE training load HTTP requests
+type TrainingLoadHandler struct {
+	db *gorm.DB
+}
+
+// NewTrainingLoadHandler creates a new TrainingLoadHandler
+func NewTrainingLoadHandler(db *gorm.DB) *TrainingLoadHandler {
+	return &TrainingLoadHandler{db: db}
+}
+
+// GetDaily returns a client's daily load with acute and chronic load and ACWR
+// for the last `days` days (default 28)
+func (h *TrainingLoadHandler) GetDaily(c *gin.Context) {
+	client, trainer, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	days := 28
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return
+		}
+		days = parsed
+	}
+
+	loads, err := services.DailyTrainingLoad(h.db, client.ID, trainer.Location(), days, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate training load"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TrainingLoadResponse{
+		Timezone: trainer.Location().String(),
+		ACWRLow:  models.ACWRLow,
+		ACWRHigh: models.ACWRHigh,
+		Days:     loads,
+	})
+}
+
+// GetWeekly returns a client's weekly load, monotony, strain and ACWR
+// for the last `weeks` weeks (default 12)
+func (h *TrainingLoadHandler) GetWeekly(c *gin.Context) {
+	client, trainer, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	weeks := 12
+	if value := c.Query("weeks"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 104 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "weeks must be between 1 and 104"})
+			return
+		}
+		weeks = parsed
+	}
+
+	loads, err := services.WeeklyTrainingLoad(h.db, client.ID, trainer.Location(), weeks, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate training load"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.TrainingLoadResponse{
+		Timezone: trainer.Location().String(),
+		ACWRLow:  models.ACWRLow,
+		ACWRHigh: models.ACWRHigh,
+		Weeks:    loads,
+	})
+}
+
+// findClient loads the client in the URL and its trainer, whose timezone the days are counted in.
+// It writes the error response and returns false if the client does not belong to the trainer.
+func (h *TrainingLoadHandler) findClient(c *gin.Context) (models.Client, models.Trainer, bool) {
+	var client models.Client
+	var trainer models.Trainer
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return client, trainer, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return client, trainer, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return client, trainer, false
+	}
+
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return client, trainer, false
+	}
+
+	return client, trainer, true
+}
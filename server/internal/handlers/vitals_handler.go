@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptmate/internal/models"
+	"ptmate/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VitalsHandler handles client vitals HTTP requests
+type VitalsHandler struct {
+	db *gorm.DB
+}
+
+// NewVitalsHandler creates a new VitalsHandler
+func NewVitalsHandler(db *gorm.DB) *VitalsHandler {
+	return &VitalsHandler{db: db}
+}
+
+// GetByClient returns a client's vitals records, newest first
+func (h *VitalsHandler) GetByClient(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var records []models.Vitals
+	if err := h.db.Where("client_id = ?", client.ID).Order("measured_at DESC").Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vitals"})
+		return
+	}
+	for i := range records {
+		records[i].SetFlags()
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// Create records a client's vitals
+func (h *VitalsHandler) Create(c *gin.Context) {
+	client, ok := h.findClient(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveVitalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	record := models.Vitals{ClientID: client.ID}
+	applyVitals(&record, req)
+	if problem := record.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	// Default to current time if not provided
+	if record.MeasuredAt.IsZero() {
+		record.MeasuredAt = time.Now()
+	}
+
+	if err := h.db.Create(&record).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create vitals"})
+		return
+	}
+	record.SetFlags()
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// GetByID returns a vitals record
+func (h *VitalsHandler) GetByID(c *gin.Context) {
+	record, ok := h.findVitals(c)
+	if !ok {
+		return
+	}
+	record.SetFlags()
+
+	c.JSON(http.StatusOK, record)
+}
+
+// Update replaces a vitals record
+func (h *VitalsHandler) Update(c *gin.Context) {
+	record, ok := h.findVitals(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveVitalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	measuredAt := record.MeasuredAt
+	applyVitals(&record, req)
+	if record.MeasuredAt.IsZero() {
+		record.MeasuredAt = measuredAt
+	}
+	if problem := record.Validate(); problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	if err := h.db.Save(&record).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update vitals"})
+		return
+	}
+	record.SetFlags()
+
+	c.JSON(http.StatusOK, record)
+}
+
+// Delete soft deletes a vitals record
+func (h *VitalsHandler) Delete(c *gin.Context) {
+	record, ok := h.findVitals(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(&record).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vitals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vitals deleted successfully"})
+}
+
+// SafetyCheck returns the client's vitals on the day of a session and the
+// warnings they raise, for the trainer to check before starting the session
+func (h *VitalsHandler) SafetyCheck(c *gin.Context) {
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	var session models.Session
+	if err := h.db.Joins("JOIN clients ON clients.id = sessions.client_id").
+		Where("sessions.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	var trainer models.Trainer
+	if err := h.db.First(&trainer, "id = ?", trainerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trainer not found"})
+		return
+	}
+
+	loc := trainer.Location()
+	vitals, warnings, err := services.SafetyCheck(h.db, session.ClientID, session.ScheduledAt, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check vitals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SafetyCheckResponse{
+		SessionID: session.ID,
+		Date:      session.ScheduledAt.In(loc).Format("2006-01-02"),
+		Safe:      len(warnings) == 0,
+		Vitals:    vitals,
+		Warnings:  warnings,
+	})
+}
+
+// findClient loads the client in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *VitalsHandler) findClient(c *gin.Context) (models.Client, bool) {
+	var client models.Client
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return client, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return client, false
+	}
+
+	if err := h.db.Where("id = ? AND trainer_id = ?", id, trainerID).First(&client).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Client not found"})
+		return client, false
+	}
+
+	return client, true
+}
+
+// findVitals loads the vitals record in the URL if its client belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *VitalsHandler) findVitals(c *gin.Context) (models.Vitals, bool) {
+	var record models.Vitals
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return record, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid vitals ID"})
+		return record, false
+	}
+
+	if err := h.db.Joins("JOIN clients ON clients.id = vitals.client_id").
+		Where("vitals.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&record).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vitals not found"})
+		return record, false
+	}
+
+	return record, true
+}
+
+// applyVitals copies the request onto the record
+func applyVitals(record *models.Vitals, req models.SaveVitalsRequest) {
+	record.SystolicMmHg = req.SystolicMmHg
+	record.DiastolicMmHg = req.DiastolicMmHg
+	record.RestingHeartRate = req.RestingHeartRate
+	record.SpO2Percent = req.SpO2Percent
+	record.GlucoseMgDl = req.GlucoseMgDl
+	record.Notes = req.Notes
+	record.MeasuredAt = req.MeasuredAt
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"ptmate/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WorkoutHandler handles workout log HTTP requests
+type WorkoutHandler struct {
+	db *gorm.DB
+}
+
+// NewWorkoutHandler creates a new WorkoutHandler
+func NewWorkoutHandler(db *gorm.DB) *WorkoutHandler {
+	return &WorkoutHandler{db: db}
+}
+
+// Get returns the workout log of a session
+func (h *WorkoutHandler) Get(c *gin.Context) {
+	session, ok := h.findSession(c)
+	if !ok {
+		return
+	}
+
+	log, err := loadWorkoutLog(h.db, session)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workout log not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workout log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, log)
+}
+
+// Save creates or replaces the workout log of a session
+func (h *WorkoutHandler) Save(c *gin.Context) {
+	session, ok := h.findSession(c)
+	if !ok {
+		return
+	}
+
+	var req models.SaveWorkoutLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !models.CanLogWorkout(session.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Workouts can only be logged for scheduled or completed sessions"})
+		return
+	}
+
+	system, err := clientUnitSystem(h.db, session.ClientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch unit system"})
+		return
+	}
+	exercises, problem := req.WorkoutExercises(orUnitSystem(req.UnitSystem, system))
+	if problem != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+		return
+	}
+
+	trainerID, _ := getTrainerID(c)
+	if !h.resolveExercises(c, trainerID, exercises) {
+		return
+	}
+
+	created := false
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		var log models.WorkoutLog
+		err := tx.Where("session_id = ?", session.ID).First(&log).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+			log = models.WorkoutLog{SessionID: session.ID, Notes: req.Notes}
+			if err := tx.Create(&log).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			if err := deleteWorkoutExercises(tx, log.ID); err != nil {
+				return err
+			}
+			log.Notes = req.Notes
+			if err := tx.Save(&log).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range exercises {
+			exercises[i].WorkoutLogID = log.ID
+		}
+		if len(exercises) > 0 {
+			return tx.Create(&exercises).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save workout log"})
+		return
+	}
+
+	log, err := loadWorkoutLog(h.db, session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workout log"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, log)
+}
+
+// Delete removes the workout log of a session
+func (h *WorkoutHandler) Delete(c *gin.Context) {
+	session, ok := h.findSession(c)
+	if !ok {
+		return
+	}
+
+	var log models.WorkoutLog
+	if err := h.db.Where("session_id = ?", session.ID).First(&log).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Workout log not found"})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteWorkoutExercises(tx, log.ID); err != nil {
+			return err
+		}
+		return tx.Delete(&log).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete workout log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Workout log deleted successfully"})
+}
+
+// findSession loads the session in the URL if it belongs to the trainer.
+// It writes the error response and returns false otherwise.
+func (h *WorkoutHandler) findSession(c *gin.Context) (models.Session, bool) {
+	var session models.Session
+
+	trainerID, ok := getTrainerID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Not authenticated"})
+		return session, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return session, false
+	}
+
+	if err := h.db.Joins("JOIN clients ON clients.id = sessions.client_id").
+		Where("sessions.id = ? AND clients.trainer_id = ?", id, trainerID).
+		First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return session, false
+	}
+
+	return session, true
+}
+
+// resolveExercises checks that the library exercises used are visible to the
+// trainer and fills in their names where none was given.
+// It writes the error response and returns false otherwise.
+func (h *WorkoutHandler) resolveExercises(c *gin.Context, trainerID uuid.UUID, exercises []models.WorkoutExercise) bool {
+	var ids []uuid.UUID
+	for _, e := range exercises {
+		if e.ExerciseID != nil {
+			ids = append(ids, *e.ExerciseID)
+		}
+	}
+	if len(ids) == 0 {
+		return true
+	}
+
+	names, err := libraryExerciseNames(h.db, trainerID, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exercises"})
+		return false
+	}
+
+	for i, e := range exercises {
+		if e.ExerciseID == nil {
+			continue
+		}
+		name, found := names[*e.ExerciseID]
+		if !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Exercise not found: " + e.ExerciseID.String()})
+			return false
+		}
+		if e.Name == "" {
+			exercises[i].Name = name
+		}
+	}
+
+	return true
+}
+
+// loadWorkoutLog loads a session's workout log with its exercises and sets in order,
+// and the sets' loads in the client's unit system
+func loadWorkoutLog(db *gorm.DB, session models.Session) (models.WorkoutLog, error) {
+	var log models.WorkoutLog
+	if err := db.Preload("Exercises", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Preload("Exercises.Sets", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("session_id = ?", session.ID).First(&log).Error; err != nil {
+		return log, err
+	}
+
+	system, err := clientUnitSystem(db, session.ClientID)
+	if err != nil {
+		return log, err
+	}
+	log.Present(system)
+	return log, nil
+}
+
+// deleteWorkoutExercises removes the exercises and sets of a workout log
+func deleteWorkoutExercises(tx *gorm.DB, logID uuid.UUID) error {
+	if err := tx.Where("workout_exercise_id IN (?)",
+		tx.Model(&models.WorkoutExercise{}).Select("id").Where("workout_log_id = ?", logID)).
+		Delete(&models.WorkoutSet{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("workout_log_id = ?", logID).Delete(&models.WorkoutExercise{}).Error
+}
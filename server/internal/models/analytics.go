@@ -0,0 +1,176 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a client is flagged as at risk
+const (
+	RiskLowAttendance = "low_attendance"
+	RiskHighNoShow    = "high_no_show"
+	RiskInactive      = "inactive"
+	RiskLowFrequency  = "low_frequency"
+)
+
+// MinRateSample is the number of past sessions needed before attendance and
+// no-show rates are used to flag a client
+const MinRateSample = 3
+
+// RetentionThresholds decide when a client is flagged as at risk
+type RetentionThresholds struct {
+	MinAttendanceRate  float64 `json:"min_attendance_rate"` // percent
+	MaxNoShowRate      float64 `json:"max_no_show_rate"`    // percent
+	MaxInactiveDays    int     `json:"max_inactive_days"`
+	MinSessionsPerWeek float64 `json:"min_sessions_per_week"`
+}
+
+// ClientMetrics are a client's retention and adherence metrics over an analysis window
+type ClientMetrics struct {
+	ClientID  uuid.UUID `json:"client_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+
+	// Past sessions in the window by status
+	Completed     int `json:"completed"`
+	NoShow        int `json:"no_show"`
+	LateCancelled int `json:"late_cancelled"`
+	Cancelled     int `json:"cancelled"`
+
+	AttendanceRate  *float64 `json:"attendance_rate"` // percent of used sessions that were completed
+	NoShowRate      *float64 `json:"no_show_rate"`    // percent of used sessions that were no-shows
+	SessionsPerWeek float64  `json:"sessions_per_week"`
+
+	LastCompletedAt      *time.Time `json:"last_completed_at"`
+	DaysSinceLastSession *int       `json:"days_since_last_session"`
+	UpcomingSessions     int        `json:"upcoming_sessions"`
+
+	PackagesFinished int      `json:"packages_finished"` // used up or expired
+	PackagesRenewed  int      `json:"packages_renewed"`  // finished and followed by another package
+	RenewalRate      *float64 `json:"renewal_rate"`      // percent
+
+	Churned     bool     `json:"churned"`
+	AtRisk      bool     `json:"at_risk"`
+	RiskReasons []string `json:"risk_reasons"`
+}
+
+// TrainerMetrics summarise retention across all of a trainer's clients
+type TrainerMetrics struct {
+	Clients         int      `json:"clients"`
+	ActiveClients   int      `json:"active_clients"`
+	AtRiskClients   int      `json:"at_risk_clients"`
+	ChurnedClients  int      `json:"churned_clients"`
+	ChurnRate       *float64 `json:"churn_rate"`
+	AttendanceRate  *float64 `json:"attendance_rate"`
+	NoShowRate      *float64 `json:"no_show_rate"`
+	SessionsPerWeek float64  `json:"sessions_per_week"` // average per active client
+	RenewalRate     *float64 `json:"renewal_rate"`
+}
+
+// AnalyticsResponse is the retention report for a trainer
+type AnalyticsResponse struct {
+	From       time.Time           `json:"from"`
+	To         time.Time           `json:"to"`
+	Thresholds RetentionThresholds `json:"thresholds"`
+	Trainer    TrainerMetrics      `json:"trainer"`
+	Clients    []ClientMetrics     `json:"clients"`
+}
+
+// Evaluate calculates the derived metrics and flags the client against the thresholds.
+// since is when the client started being followed: the window start or when they joined.
+func (m *ClientMetrics) Evaluate(t RetentionThresholds, since, now time.Time) {
+	observedDays := int(now.Sub(since).Hours() / 24)
+
+	used := m.Completed + m.NoShow + m.LateCancelled
+	m.AttendanceRate = percent(m.Completed, used)
+	m.NoShowRate = percent(m.NoShow, used)
+	m.RenewalRate = percent(m.PackagesRenewed, m.PackagesFinished)
+
+	weeks := float64(observedDays) / 7
+	if weeks < 1 {
+		weeks = 1
+	}
+	m.SessionsPerWeek = roundRate(float64(m.Completed) / weeks)
+
+	inactiveDays := observedDays
+	if m.LastCompletedAt != nil {
+		days := int(now.Sub(*m.LastCompletedAt).Hours() / 24)
+		m.DaysSinceLastSession = &days
+		inactiveDays = days
+	}
+
+	// Churned clients trained before but have nothing in the window or booked ahead
+	m.Churned = m.LastCompletedAt != nil && m.Completed == 0 && m.UpcomingSessions == 0
+
+	m.RiskReasons = []string{}
+	if used >= MinRateSample {
+		if *m.AttendanceRate < t.MinAttendanceRate {
+			m.RiskReasons = append(m.RiskReasons, RiskLowAttendance)
+		}
+		if *m.NoShowRate > t.MaxNoShowRate {
+			m.RiskReasons = append(m.RiskReasons, RiskHighNoShow)
+		}
+	}
+	if t.MaxInactiveDays > 0 && inactiveDays > t.MaxInactiveDays {
+		m.RiskReasons = append(m.RiskReasons, RiskInactive)
+	}
+	// Frequency needs at least two weeks of history to mean anything
+	if observedDays >= 14 && m.SessionsPerWeek < t.MinSessionsPerWeek {
+		m.RiskReasons = append(m.RiskReasons, RiskLowFrequency)
+	}
+	m.AtRisk = len(m.RiskReasons) > 0
+}
+
+// SummariseRetention aggregates evaluated client metrics into trainer metrics
+func SummariseRetention(clients []ClientMetrics) TrainerMetrics {
+	summary := TrainerMetrics{Clients: len(clients)}
+
+	var completed, noShow, used, finished, renewed int
+	var weekly float64
+	everActive := 0
+	for _, m := range clients {
+		completed += m.Completed
+		noShow += m.NoShow
+		used += m.Completed + m.NoShow + m.LateCancelled
+		finished += m.PackagesFinished
+		renewed += m.PackagesRenewed
+
+		if m.AtRisk {
+			summary.AtRiskClients++
+		}
+		if m.LastCompletedAt != nil || m.UpcomingSessions > 0 {
+			everActive++
+		}
+		if m.Churned {
+			summary.ChurnedClients++
+		} else if m.Completed > 0 || m.UpcomingSessions > 0 {
+			summary.ActiveClients++
+			weekly += m.SessionsPerWeek
+		}
+	}
+
+	summary.ChurnRate = percent(summary.ChurnedClients, everActive)
+	summary.AttendanceRate = percent(completed, used)
+	summary.NoShowRate = percent(noShow, used)
+	summary.RenewalRate = percent(renewed, finished)
+	if summary.ActiveClients > 0 {
+		summary.SessionsPerWeek = roundRate(weekly / float64(summary.ActiveClients))
+	}
+
+	return summary
+}
+
+// percent returns part/total as a percentage, or nil when total is zero
+func percent(part, total int) *float64 {
+	if total == 0 {
+		return nil
+	}
+	rate := roundRate(float64(part) * 100 / float64(total))
+	return &rate
+}
+
+// roundRate rounds a rate to one decimal place
+func roundRate(rate float64) float64 {
+	return float64(int64(rate*10+0.5)) / 10
+}
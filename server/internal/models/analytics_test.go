@@ -0,0 +1,153 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClientMetricsEvaluate(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		at := now.AddDate(0, 0, -days)
+		return &at
+	}
+	thresholds := RetentionThresholds{
+		MinAttendanceRate:  75,
+		MaxNoShowRate:      20,
+		MaxInactiveDays:    14,
+		MinSessionsPerWeek: 1,
+	}
+
+	tests := []struct {
+		name            string
+		metrics         ClientMetrics
+		since           time.Time
+		wantAttendance  *float64
+		wantNoShow      *float64
+		wantPerWeek     float64
+		wantChurned     bool
+		wantRiskReasons []string
+	}{
+		{
+			name: "regular client",
+			metrics: ClientMetrics{
+				Completed: 9, NoShow: 1,
+				LastCompletedAt: daysAgo(2), UpcomingSessions: 2,
+			},
+			since:           now.AddDate(0, 0, -28),
+			wantAttendance:  float(90),
+			wantNoShow:      float(10),
+			wantPerWeek:     2.3,
+			wantRiskReasons: []string{},
+		},
+		{
+			name: "low attendance and frequent no-shows",
+			metrics: ClientMetrics{
+				Completed: 2, NoShow: 1, LateCancelled: 1,
+				LastCompletedAt: daysAgo(3), UpcomingSessions: 1,
+			},
+			since:           now.AddDate(0, 0, -28),
+			wantAttendance:  float(50),
+			wantNoShow:      float(25),
+			wantPerWeek:     0.5,
+			wantRiskReasons: []string{RiskLowAttendance, RiskHighNoShow, RiskLowFrequency},
+		},
+		{
+			name: "rates need enough sessions",
+			metrics: ClientMetrics{
+				Completed: 1, NoShow: 1,
+				LastCompletedAt: daysAgo(1), UpcomingSessions: 1,
+			},
+			since:           now.AddDate(0, 0, -7),
+			wantAttendance:  float(50),
+			wantNoShow:      float(50),
+			wantPerWeek:     1,
+			wantRiskReasons: []string{},
+		},
+		{
+			name: "churned",
+			metrics: ClientMetrics{
+				LastCompletedAt: daysAgo(40),
+			},
+			since:           now.AddDate(0, 0, -28),
+			wantPerWeek:     0,
+			wantChurned:     true,
+			wantRiskReasons: []string{RiskInactive, RiskLowFrequency},
+		},
+		{
+			name:            "new client without sessions yet",
+			metrics:         ClientMetrics{UpcomingSessions: 1},
+			since:           now.AddDate(0, 0, -3),
+			wantRiskReasons: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.metrics
+			m.Evaluate(thresholds, tt.since, now)
+
+			if !reflect.DeepEqual(m.AttendanceRate, tt.wantAttendance) {
+				t.Errorf("AttendanceRate = %v, want %v", deref(m.AttendanceRate), deref(tt.wantAttendance))
+			}
+			if !reflect.DeepEqual(m.NoShowRate, tt.wantNoShow) {
+				t.Errorf("NoShowRate = %v, want %v", deref(m.NoShowRate), deref(tt.wantNoShow))
+			}
+			if m.SessionsPerWeek != tt.wantPerWeek {
+				t.Errorf("SessionsPerWeek = %v, want %v", m.SessionsPerWeek, tt.wantPerWeek)
+			}
+			if m.Churned != tt.wantChurned {
+				t.Errorf("Churned = %v, want %v", m.Churned, tt.wantChurned)
+			}
+			if !reflect.DeepEqual(m.RiskReasons, tt.wantRiskReasons) {
+				t.Errorf("RiskReasons = %v, want %v", m.RiskReasons, tt.wantRiskReasons)
+			}
+			if m.AtRisk != (len(tt.wantRiskReasons) > 0) {
+				t.Errorf("AtRisk = %v with reasons %v", m.AtRisk, m.RiskReasons)
+			}
+		})
+	}
+}
+
+func TestSummariseRetention(t *testing.T) {
+	last := time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC)
+	clients := []ClientMetrics{
+		{Completed: 8, NoShow: 2, SessionsPerWeek: 2, LastCompletedAt: &last, PackagesFinished: 2, PackagesRenewed: 1},
+		{Completed: 3, LateCancelled: 1, SessionsPerWeek: 1, LastCompletedAt: &last, AtRisk: true, PackagesFinished: 1, PackagesRenewed: 1},
+		{LastCompletedAt: &last, Churned: true, AtRisk: true, PackagesFinished: 1},
+		{UpcomingSessions: 1},
+		{},
+	}
+
+	got := SummariseRetention(clients)
+	want := TrainerMetrics{
+		Clients:         5,
+		ActiveClients:   3,
+		AtRiskClients:   2,
+		ChurnedClients:  1,
+		ChurnRate:       float(25),
+		AttendanceRate:  float(78.6),
+		NoShowRate:      float(14.3),
+		SessionsPerWeek: 1,
+		RenewalRate:     float(50),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SummariseRetention() = %+v, want %+v", got, want)
+	}
+
+	if empty := SummariseRetention(nil); empty.ChurnRate != nil || empty.AttendanceRate != nil || empty.RenewalRate != nil {
+		t.Errorf("SummariseRetention(nil) = %+v, want nil rates", empty)
+	}
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func deref(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
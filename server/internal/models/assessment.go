@@ -128,3 +128,8 @@ func (a *Assessment) ScoreLevel() string {
 	}
 	return "good"
 }
+
+// HasCardiacFlags returns true if the PAR-Q answers report heart problems, chest pain or dizziness
+func (a *Assessment) HasCardiacFlags() bool {
+	return a.ParqHeartProblem || a.ParqChestPain || a.ParqDizziness
+}
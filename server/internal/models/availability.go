@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WorkingHours is one block of a trainer's weekly working-hour template
+// A weekday may have several blocks (e.g. 07:00-12:00 and 16:00-21:00)
+type WorkingHours struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrainerID uuid.UUID `gorm:"type:uuid;not null;index" json:"trainer_id"`
+	Weekday   int       `gorm:"not null" json:"weekday"`                    // 0 = Sunday ... 6 = Saturday
+	StartTime string    `gorm:"type:varchar(5);not null" json:"start_time"` // HH:MM in trainer's timezone
+	EndTime   string    `gorm:"type:varchar(5);not null" json:"end_time"`   // HH:MM in trainer's timezone
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AvailabilityOverride replaces the weekly template for a specific date
+// Several overrides on the same date add up; IsUnavailable closes the whole day
+type AvailabilityOverride struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrainerID     uuid.UUID `gorm:"type:uuid;not null;index" json:"trainer_id"`
+	Date          string    `gorm:"type:varchar(10);not null;index" json:"date"` // YYYY-MM-DD
+	StartTime     string    `gorm:"type:varchar(5)" json:"start_time,omitempty"`
+	EndTime       string    `gorm:"type:varchar(5)" json:"end_time,omitempty"`
+	IsUnavailable bool      `gorm:"not null;default:false" json:"is_unavailable"`
+	Note          string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TimeOff blocks a trainer's calendar (vacation, sick leave, etc.)
+type TimeOff struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrainerID uuid.UUID      `gorm:"type:uuid;not null;index" json:"trainer_id"`
+	StartsAt  time.Time      `gorm:"not null;index" json:"starts_at"`
+	EndsAt    time.Time      `gorm:"not null;index" json:"ends_at"`
+	Reason    string         `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// WorkingHoursEntry is a single block in a SetWorkingHoursRequest
+type WorkingHoursEntry struct {
+	Weekday   int    `json:"weekday" binding:"min=0,max=6"`
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
+// SetWorkingHoursRequest replaces the whole weekly template
+type SetWorkingHoursRequest struct {
+	Hours []WorkingHoursEntry `json:"hours" binding:"dive"`
+}
+
+// CreateAvailabilityOverrideRequest represents the request body for a date override
+type CreateAvailabilityOverrideRequest struct {
+	Date          string `json:"date" binding:"required"`
+	StartTime     string `json:"start_time"`
+	EndTime       string `json:"end_time"`
+	IsUnavailable bool   `json:"is_unavailable"`
+	Note          string `json:"note"`
+}
+
+// CreateTimeOffRequest represents the request body for a time-off block
+type CreateTimeOffRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+// TimeSlot is a bookable period
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// AvailableSlotsResponse is returned by the free-slot search
+type AvailableSlotsResponse struct {
+	Timezone        string     `json:"timezone"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Slots           []TimeSlot `json:"slots"`
+}
+
+// TableName overrides the table name
+func (WorkingHours) TableName() string {
+	return "working_hours"
+}
+
+// TableName overrides the table name
+func (AvailabilityOverride) TableName() string {
+	return "availability_overrides"
+}
+
+// TableName overrides the table name
+func (TimeOff) TableName() string {
+	return "time_off"
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Bioimpedance is a reading from a bioimpedance (InBody-style) scale,
+// stored as the device reports it
+type Bioimpedance struct {
+	ID                   uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID             uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	Device               string         `gorm:"size:100" json:"device,omitempty"`
+	WeightKg             *float64       `json:"weight_kg,omitempty"`
+	BodyFatPercent       *float64       `json:"body_fat_percent,omitempty"`
+	SkeletalMuscleMassKg *float64       `json:"skeletal_muscle_mass_kg,omitempty"`
+	VisceralFatLevel     *float64       `json:"visceral_fat_level,omitempty"`
+	BMRKcal              *int           `json:"bmr_kcal,omitempty"` // basal metabolic rate
+	TotalBodyWaterL      *float64       `json:"total_body_water_l,omitempty"`
+	Notes                string         `gorm:"type:text" json:"notes,omitempty"`
+	MeasuredAt           time.Time      `gorm:"not null;index" json:"measured_at"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Calculated for responses and not stored
+	UnitSystem UnitSystem           `gorm:"-" json:"unit_system"`
+	Values     map[string]UnitValue `gorm:"-" json:"values"` // weight and skeletal muscle mass in unit_system
+}
+
+// SaveBioimpedanceRequest represents the request body for creating or updating a bioimpedance reading.
+// Values can be given instead of the _kg fields, in unit_system (defaults to the client's).
+type SaveBioimpedanceRequest struct {
+	Device               string             `json:"device"`
+	WeightKg             *float64           `json:"weight_kg" binding:"omitempty,gt=0"`
+	BodyFatPercent       *float64           `json:"body_fat_percent" binding:"omitempty,gte=0,lt=100"`
+	SkeletalMuscleMassKg *float64           `json:"skeletal_muscle_mass_kg" binding:"omitempty,gt=0"`
+	VisceralFatLevel     *float64           `json:"visceral_fat_level" binding:"omitempty,gte=0"`
+	BMRKcal              *int               `json:"bmr_kcal" binding:"omitempty,gt=0"`
+	TotalBodyWaterL      *float64           `json:"total_body_water_l" binding:"omitempty,gt=0"`
+	Values               map[string]float64 `json:"values"`
+	UnitSystem           UnitSystem         `json:"unit_system"`
+	Notes                string             `json:"notes"`
+	MeasuredAt           time.Time          `json:"measured_at"`
+}
+
+// TableName overrides the table name
+func (Bioimpedance) TableName() string {
+	return "bioimpedance_records"
+}
+
+// unitFields lists the reading's values that have a unit
+func (b *Bioimpedance) unitFields() []unitField {
+	return []unitField{
+		{key: "weight", dimension: DimensionMass, value: &b.WeightKg},
+		{key: "skeletal_muscle_mass", dimension: DimensionMass, value: &b.SkeletalMuscleMassKg},
+	}
+}
+
+// Present fills in the reading's values in the unit system for a response
+func (b *Bioimpedance) Present(system UnitSystem) {
+	b.UnitSystem = system
+	b.Values = presentUnitFields(system, b.unitFields())
+}
+
+// ApplyValues stores values given in the unit system on the reading in kg.
+// It returns a message describing the problem if a value is invalid.
+func (b *Bioimpedance) ApplyValues(system UnitSystem, values map[string]float64) string {
+	return applyUnitFields(system, values, b.unitFields())
+}
@@ -0,0 +1,90 @@
+package models
+
+import "math"
+
+// BodyComposition holds the values derived from a measurement.
+// Height and age fall back to the client's when the measurement has none.
+type BodyComposition struct {
+	HeightCm           *float64 `json:"height_cm"`
+	Age                *int     `json:"age"`
+	BMI                *float64 `json:"bmi"`
+	WaistToHipRatio    *float64 `json:"waist_to_hip_ratio"`
+	WaistToHeightRatio *float64 `json:"waist_to_height_ratio"`
+	BodyFatPercent     *float64 `json:"body_fat_percent"` // US Navy formula; needs the client's sex
+}
+
+// Derive calculates the measurement's body composition for the given client
+func (m *Measurement) Derive(client Client) {
+	d := BodyComposition{HeightCm: m.HeightCm, Age: m.Age}
+	if d.HeightCm == nil {
+		d.HeightCm = client.HeightCm
+	}
+	if d.Age == nil {
+		d.Age = client.Age
+	}
+
+	if m.WeightKg != nil && d.HeightCm != nil && *d.HeightCm > 0 {
+		meters := *d.HeightCm / 100
+		d.BMI = round1(*m.WeightKg / (meters * meters))
+	}
+	if m.WaistCm != nil && m.HipCm != nil && *m.HipCm > 0 {
+		d.WaistToHipRatio = round2(*m.WaistCm / *m.HipCm)
+	}
+	if m.WaistCm != nil && d.HeightCm != nil && *d.HeightCm > 0 {
+		d.WaistToHeightRatio = round2(*m.WaistCm / *d.HeightCm)
+	}
+	d.BodyFatPercent = navyBodyFat(client.Sex, d.HeightCm, m.NeckCm, m.WaistCm, m.HipCm)
+
+	m.Derived = d
+}
+
+// navyBodyFat estimates body fat with the US Navy circumference formula (in cm):
+//
+//	male:   495 / (1.0324 - 0.19077 × log10(waist - neck) + 0.15456 × log10(height)) - 450
+//	female: 495 / (1.29579 - 0.35004 × log10(waist + hip - neck) + 0.22100 × log10(height)) - 450
+//
+// It returns nil if a circumference the formula needs is missing.
+func navyBodyFat(sex Sex, heightCm, neckCm, waistCm, hipCm *float64) *float64 {
+	if heightCm == nil || neckCm == nil || waistCm == nil || *heightCm <= 0 {
+		return nil
+	}
+
+	var density float64
+	switch sex {
+	case SexMale:
+		girth := *waistCm - *neckCm
+		if girth <= 0 {
+			return nil
+		}
+		density = 1.0324 - 0.19077*math.Log10(girth) + 0.15456*math.Log10(*heightCm)
+	case SexFemale:
+		if hipCm == nil {
+			return nil
+		}
+		girth := *waistCm + *hipCm - *neckCm
+		if girth <= 0 {
+			return nil
+		}
+		density = 1.29579 - 0.35004*math.Log10(girth) + 0.22100*math.Log10(*heightCm)
+	default:
+		return nil
+	}
+
+	percent := 495/density - 450
+	if percent <= 0 || percent >= 100 {
+		return nil
+	}
+	return round1(percent)
+}
+
+// round1 rounds to one decimal place
+func round1(value float64) *float64 {
+	rounded := math.Round(value*10) / 10
+	return &rounded
+}
+
+// round2 rounds to two decimal places
+func round2(value float64) *float64 {
+	rounded := math.Round(value*100) / 100
+	return &rounded
+}
@@ -0,0 +1,73 @@
+package models
+
+import "testing"
+
+func TestNavyBodyFat(t *testing.T) {
+	tests := []struct {
+		name   string
+		sex    Sex
+		height *float64
+		neck   *float64
+		waist  *float64
+		hip    *float64
+		want   *float64
+	}{
+		{
+			name:   "male",
+			sex:    SexMale,
+			height: float(180),
+			neck:   float(38),
+			waist:  float(85),
+			want:   float(16.1),
+		},
+		{
+			name:   "female",
+			sex:    SexFemale,
+			height: float(165),
+			neck:   float(33),
+			waist:  float(75),
+			hip:    float(100),
+			want:   float(29.4),
+		},
+		{
+			name:   "female without hip",
+			sex:    SexFemale,
+			height: float(165),
+			neck:   float(33),
+			waist:  float(75),
+		},
+		{
+			name:   "unknown sex",
+			height: float(180),
+			neck:   float(38),
+			waist:  float(85),
+		},
+		{
+			name:  "missing height",
+			sex:   SexMale,
+			neck:  float(38),
+			waist: float(85),
+		},
+		{
+			name:   "neck as large as the waist",
+			sex:    SexMale,
+			height: float(180),
+			neck:   float(40),
+			waist:  float(40),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := navyBodyFat(tt.sex, tt.height, tt.neck, tt.waist, tt.hip)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("navyBodyFat() = %v, want nil", *got)
+			case tt.want != nil && got == nil:
+				t.Errorf("navyBodyFat() = nil, want %v", *tt.want)
+			case tt.want != nil && *got != *tt.want:
+				t.Errorf("navyBodyFat() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
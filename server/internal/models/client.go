@@ -7,6 +7,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// Sex is a client's sex as used by body-composition formulas
+type Sex string
+
+const (
+	SexMale   Sex = "male"
+	SexFemale Sex = "female"
+)
+
 // Client represents a personal training client
 type Client struct {
 	ID               uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
@@ -17,6 +25,8 @@ type Client struct {
 	Email            string         `gorm:"size:255" json:"email"`
 	Age              *int           `json:"age,omitempty"`
 	HeightCm         *float64       `json:"height_cm,omitempty"`
+	Sex              Sex            `gorm:"type:varchar(10)" json:"sex,omitempty"`         // used by body-fat formulas
+	UnitSystem       *UnitSystem    `gorm:"type:varchar(10)" json:"unit_system,omitempty"` // overrides the trainer's
 	TotalPackageSize int            `gorm:"not null;default:0" json:"total_package_size"`
 	PackageStartDate *time.Time     `json:"package_start_date,omitempty"`
 	Notes            string         `gorm:"type:text" json:"notes,omitempty"`
@@ -27,42 +37,68 @@ type Client struct {
 	// Relationships
 	Sessions     []Session     `gorm:"foreignKey:ClientID" json:"sessions,omitempty"`
 	Measurements []Measurement `gorm:"foreignKey:ClientID" json:"measurements,omitempty"`
+
+	// Calculated for responses and not stored
+	Values map[string]UnitValue `gorm:"-" json:"values,omitempty"` // height in the client's unit system
 }
 
 // ClientResponse includes calculated fields for API responses
 type ClientResponse struct {
 	Client
-	RemainingSessions int `json:"remaining_sessions"`
-	CompletedSessions int `json:"completed_sessions"`
-	NoShowSessions    int `json:"no_show_sessions"`
-	CancelledSessions int `json:"cancelled_sessions"`
-	ScheduledSessions int `json:"scheduled_sessions"`
+	RemainingSessions       int `json:"remaining_sessions"`
+	CompletedSessions       int `json:"completed_sessions"`
+	NoShowSessions          int `json:"no_show_sessions"`
+	CancelledSessions       int `json:"cancelled_sessions"`
+	LateCancelledSessions   int `json:"late_cancelled_sessions"`
+	ScheduledSessions       int `json:"scheduled_sessions"`
+	NeedsAttendanceSessions int `json:"needs_attendance_sessions"` // finished sessions waiting to be marked
+	UnassignedSessions      int `json:"unassigned_sessions"`       // used sessions no package covered
+
+	// Packages lists each package with its own remaining sessions
+	Packages []PackageResponse `json:"packages"`
+}
+
+// ClientTrainingStats summarises a client's training history.
+// Weeks start on Monday in the trainer's timezone.
+type ClientTrainingStats struct {
+	CurrentStreakWeeks int        `json:"current_streak_weeks"` // consecutive weeks with a completed session, up to this week
+	LongestStreakWeeks int        `json:"longest_streak_weeks"`
+	TotalWorkouts      int        `json:"total_workouts"`
+	MinutesTrained     int        `json:"minutes_trained"`
+	LastSessionAt      *time.Time `json:"last_session_at"`
+	NextSessionAt      *time.Time `json:"next_session_at"`
 }
 
 // CreateClientRequest represents the request body for creating a client
 type CreateClientRequest struct {
-	FirstName        string     `json:"first_name" binding:"required"`
-	LastName         string     `json:"last_name" binding:"required"`
-	Phone            string     `json:"phone"`
-	Email            string     `json:"email"`
-	Age              *int       `json:"age"`
-	HeightCm         *float64   `json:"height_cm"`
-	TotalPackageSize int        `json:"total_package_size"`
-	PackageStartDate *time.Time `json:"package_start_date"`
-	Notes            string     `json:"notes"`
+	FirstName        string             `json:"first_name" binding:"required"`
+	LastName         string             `json:"last_name" binding:"required"`
+	Phone            string             `json:"phone"`
+	Email            string             `json:"email"`
+	Age              *int               `json:"age"`
+	HeightCm         *float64           `json:"height_cm"`
+	Sex              Sex                `json:"sex" binding:"omitempty,oneof=male female"`
+	UnitSystem       UnitSystem         `json:"unit_system" binding:"omitempty,oneof=metric imperial"`
+	Values           map[string]float64 `json:"values"` // instead of height_cm, in unit_system or else the trainer's
+	TotalPackageSize int                `json:"total_package_size"`
+	PackageStartDate *time.Time         `json:"package_start_date"`
+	Notes            string             `json:"notes"`
 }
 
 // UpdateClientRequest represents the request body for updating a client
 type UpdateClientRequest struct {
-	FirstName        *string    `json:"first_name"`
-	LastName         *string    `json:"last_name"`
-	Phone            *string    `json:"phone"`
-	Email            *string    `json:"email"`
-	Age              *int       `json:"age"`
-	HeightCm         *float64   `json:"height_cm"`
-	TotalPackageSize *int       `json:"total_package_size"`
-	PackageStartDate *time.Time `json:"package_start_date"`
-	Notes            *string    `json:"notes"`
+	FirstName        *string            `json:"first_name"`
+	LastName         *string            `json:"last_name"`
+	Phone            *string            `json:"phone"`
+	Email            *string            `json:"email"`
+	Age              *int               `json:"age"`
+	HeightCm         *float64           `json:"height_cm"`
+	Sex              *Sex               `json:"sex" binding:"omitempty,oneof='' male female"`             // empty clears it
+	UnitSystem       *UnitSystem        `json:"unit_system" binding:"omitempty,oneof='' metric imperial"` // empty clears the override
+	Values           map[string]float64 `json:"values"`                                                   // instead of height_cm, in the client's unit system after this update
+	TotalPackageSize *int               `json:"total_package_size"`
+	PackageStartDate *time.Time         `json:"package_start_date"`
+	Notes            *string            `json:"notes"`
 }
 
 // TableName overrides the table name
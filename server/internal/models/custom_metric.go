@@ -0,0 +1,131 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxCustomMetricDecimals is the highest decimal precision a custom metric can have
+const MaxCustomMetricDecimals = 4
+
+// CustomMetric is a value a trainer measures beyond the built-in measurement fields,
+// e.g. calf circumference or resting heart rate. Its values are entered and returned
+// in the metric's own unit, whatever the unit system.
+type CustomMetric struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrainerID uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_custom_metrics_trainer_key,where:deleted_at IS NULL" json:"trainer_id"`
+	Key       string         `gorm:"size:50;not null;uniqueIndex:idx_custom_metrics_trainer_key,where:deleted_at IS NULL" json:"key"` // name used in measurement values, unique per trainer
+	Name      string         `gorm:"size:100;not null" json:"name"`
+	Unit      string         `gorm:"size:20" json:"unit"`
+	Decimals  int            `gorm:"not null;default:1" json:"decimals"`
+	Min       *float64       `json:"min"`
+	Max       *float64       `json:"max"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// CustomMetricValue is a measurement's value of a custom metric
+type CustomMetricValue struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	MeasurementID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_custom_metric_values_measurement_metric" json:"measurement_id"`
+	CustomMetricID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_custom_metric_values_measurement_metric;index" json:"custom_metric_id"`
+	Value          float64   `gorm:"not null" json:"value"`
+
+	// Relationship
+	CustomMetric CustomMetric `gorm:"foreignKey:CustomMetricID" json:"-"`
+}
+
+// CreateCustomMetricRequest represents the request body for creating a custom metric
+type CreateCustomMetricRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Key      string   `json:"key"` // defaults to the name in lower case with underscores
+	Unit     string   `json:"unit"`
+	Decimals *int     `json:"decimals"` // defaults to 1
+	Min      *float64 `json:"min"`
+	Max      *float64 `json:"max"`
+}
+
+// UpdateCustomMetricRequest represents the request body for updating a custom metric.
+// The key cannot change, since requests refer to the metric by it.
+type UpdateCustomMetricRequest struct {
+	Name     *string  `json:"name"`
+	Unit     *string  `json:"unit"`
+	Decimals *int     `json:"decimals"`
+	Min      *float64 `json:"min"`
+	Max      *float64 `json:"max"`
+}
+
+// TableName overrides the table name
+func (CustomMetric) TableName() string {
+	return "custom_metrics"
+}
+
+// TableName overrides the table name
+func (CustomMetricValue) TableName() string {
+	return "custom_metric_values"
+}
+
+// CustomMetricKey turns a metric name into a key, e.g. "Resting Heart Rate" into "resting_heart_rate"
+func CustomMetricKey(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if underscore && b.Len() > 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	return b.String()
+}
+
+// Validate checks the metric's key, precision and range and returns a
+// description of the first problem found
+func (m CustomMetric) Validate() string {
+	if m.Key == "" {
+		return "key is required"
+	}
+	for _, r := range m.Key {
+		if !unicode.IsLower(r) && !unicode.IsDigit(r) && r != '_' {
+			return "key can only contain lower case letters, digits and underscores"
+		}
+	}
+	if _, builtIn := MeasurementFieldByKey(m.Key); builtIn {
+		return "key is already a built-in measurement field: " + m.Key
+	}
+	if m.Decimals < 0 || m.Decimals > MaxCustomMetricDecimals {
+		return fmt.Sprintf("decimals must be between 0 and %d", MaxCustomMetricDecimals)
+	}
+	if m.Min != nil && m.Max != nil && *m.Min >= *m.Max {
+		return "min must be less than max"
+	}
+	return ""
+}
+
+// Round rounds a value to the metric's precision
+func (m CustomMetric) Round(value float64) float64 {
+	scale := math.Pow10(m.Decimals)
+	return math.Round(value*scale) / scale
+}
+
+// CheckRange returns a description of the problem if a value is outside the metric's range
+func (m CustomMetric) CheckRange(value float64) string {
+	if m.Min != nil && value < *m.Min {
+		return fmt.Sprintf("%s must be at least %g", m.Key, *m.Min)
+	}
+	if m.Max != nil && value > *m.Max {
+		return fmt.Sprintf("%s must be at most %g", m.Key, *m.Max)
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCustomMetricKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Resting Heart Rate", "resting_heart_rate"},
+		{"  Calf (left)  ", "calf_left"},
+		{"VO2 max", "vo2_max"},
+		{"Grip-strength / right", "grip_strength_right"},
+		{"***", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CustomMetricKey(tt.name); got != tt.want {
+				t.Errorf("CustomMetricKey(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomMetricValidate(t *testing.T) {
+	min, max := 30.0, 220.0
+
+	tests := []struct {
+		name   string
+		metric CustomMetric
+		wantOK bool
+	}{
+		{"valid", CustomMetric{Key: "resting_heart_rate", Decimals: 0, Min: &min, Max: &max}, true},
+		{"no key", CustomMetric{Decimals: 1}, false},
+		{"upper case key", CustomMetric{Key: "Calf", Decimals: 1}, false},
+		{"built-in key", CustomMetric{Key: "weight", Decimals: 1}, false},
+		{"too many decimals", CustomMetric{Key: "calf", Decimals: MaxCustomMetricDecimals + 1}, false},
+		{"negative decimals", CustomMetric{Key: "calf", Decimals: -1}, false},
+		{"min not below max", CustomMetric{Key: "calf", Decimals: 1, Min: &max, Max: &min}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := tt.metric.Validate()
+			if tt.wantOK && problem != "" {
+				t.Errorf("Validate() = %q, want no problem", problem)
+			}
+			if !tt.wantOK && problem == "" {
+				t.Error("Validate() found no problem")
+			}
+		})
+	}
+}
+
+func TestCustomMetricRoundAndCheckRange(t *testing.T) {
+	min, max := 30.0, 220.0
+	metric := CustomMetric{Key: "resting_heart_rate", Min: &min, Max: &max}
+
+	rounds := []struct {
+		decimals int
+		value    float64
+		want     float64
+	}{
+		{0, 57.5, 58},
+		{1, 38.25, 38.3},
+		{2, 12.344, 12.34},
+		{4, 0.123456, 0.1235},
+	}
+	for _, tt := range rounds {
+		metric.Decimals = tt.decimals
+		if got := metric.Round(tt.value); got != tt.want {
+			t.Errorf("Round(%v) with %d decimals = %v, want %v", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+
+	ranges := []struct {
+		value  float64
+		wantOK bool
+	}{
+		{30, true},
+		{220, true},
+		{29.9, false},
+		{220.1, false},
+	}
+	for _, tt := range ranges {
+		problem := metric.CheckRange(tt.value)
+		if tt.wantOK != (problem == "") {
+			t.Errorf("CheckRange(%v) = %q, want ok %v", tt.value, problem, tt.wantOK)
+		}
+	}
+
+	if problem := (CustomMetric{Key: "calf"}).CheckRange(-1000); problem != "" {
+		t.Errorf("CheckRange() without bounds = %q, want no problem", problem)
+	}
+}
+
+func TestApplyCustomValues(t *testing.T) {
+	calf := CustomMetric{ID: uuid.New(), Key: "calf", Unit: "cm", Decimals: 1}
+	heartRate := CustomMetric{ID: uuid.New(), Key: "resting_heart_rate", Unit: "bpm", Decimals: 0}
+	deleted := uuid.New()
+
+	m := Measurement{CustomValues: []CustomMetricValue{
+		{CustomMetricID: calf.ID, Value: 38.25, CustomMetric: calf},
+		{CustomMetricID: heartRate.ID, Value: 60, CustomMetric: heartRate},
+		{CustomMetricID: deleted, Value: 12}, // metric since deleted
+	}}
+	req := CreateMeasurementRequest{Values: map[string]float64{"resting_heart_rate": 57.6}}
+	if problem := req.ApplyValues(&m, UnitSystemMetric, []CustomMetric{calf, heartRate}); problem != "" {
+		t.Fatalf("ApplyValues() = %q, want no problem", problem)
+	}
+
+	if len(m.CustomValues) != 3 {
+		t.Fatalf("got %d custom values, want the 3 stored ones", len(m.CustomValues))
+	}
+	if m.CustomValues[1].Value != 58 {
+		t.Errorf("resting_heart_rate = %v, want the new value 58", m.CustomValues[1].Value)
+	}
+	if m.CustomValues[2].Value != 12 {
+		t.Errorf("value of the deleted metric = %v, want it kept", m.CustomValues[2].Value)
+	}
+
+	m.Present(UnitSystemMetric)
+	if got := m.Values["calf"].Value; got != 38.25 {
+		t.Errorf("calf = %v, want the stored 38.25 without rounding", got)
+	}
+	if len(m.Values) != 2 {
+		t.Errorf("got values %v, want only the metrics that still exist", m.Values)
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Languages exercises are translated into
+const (
+	LanguageEnglish = "en"
+	LanguageTurkish = "tr"
+)
+
+// MuscleGroups are the muscle groups an exercise can target
+var MuscleGroups = []string{
+	"chest", "upper_back", "lats", "lower_back", "traps", "shoulders",
+	"biceps", "triceps", "forearms", "abs", "obliques",
+	"glutes", "quads", "hamstrings", "adductors", "abductors", "calves",
+	"full_body",
+}
+
+// Equipment is the equipment an exercise can need
+var Equipment = []string{
+	"bodyweight", "barbell", "dumbbell", "kettlebell", "cable", "machine",
+	"band", "smith_machine", "trap_bar", "medicine_ball", "pull_up_bar", "bench", "other",
+}
+
+// MovementPatterns are the fundamental movement patterns of exercises
+var MovementPatterns = []string{
+	"squat", "hinge", "lunge", "horizontal_push", "vertical_push",
+	"horizontal_pull", "vertical_pull", "carry", "rotation", "anti_rotation",
+	"isolation", "conditioning", "mobility",
+}
+
+// ExerciseTranslation is an exercise's name and instructions in one language
+type ExerciseTranslation struct {
+	Name         string `json:"name"`
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// Exercise is an entry in the exercise library. Exercises without a trainer
+// belong to the global library; the others are a trainer's private custom exercises.
+type Exercise struct {
+	ID               uuid.UUID                      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrainerID        *uuid.UUID                     `gorm:"type:uuid;index" json:"trainer_id,omitempty"`
+	Key              string                         `gorm:"size:100;uniqueIndex:idx_exercises_global_key,where:trainer_id IS NULL" json:"key,omitempty"` // stable identifier of global exercises, unique among them
+	Name             string                         `gorm:"size:255;not null" json:"name"`                                                               // English name
+	PrimaryMuscles   []string                       `gorm:"serializer:json;type:jsonb" json:"primary_muscles"`
+	SecondaryMuscles []string                       `gorm:"serializer:json;type:jsonb" json:"secondary_muscles"`
+	Equipment        string                         `gorm:"size:50" json:"equipment"`
+	MovementPattern  string                         `gorm:"size:50" json:"movement_pattern"`
+	Translations     map[string]ExerciseTranslation `gorm:"serializer:json;type:jsonb" json:"translations"`
+	CreatedAt        time.Time                      `json:"created_at"`
+	UpdatedAt        time.Time                      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt                 `gorm:"index" json:"-"`
+}
+
+// CreateExerciseRequest represents the request body for creating a custom exercise
+type CreateExerciseRequest struct {
+	Name             string                         `json:"name" binding:"required"`
+	PrimaryMuscles   []string                       `json:"primary_muscles" binding:"required,min=1"`
+	SecondaryMuscles []string                       `json:"secondary_muscles"`
+	Equipment        string                         `json:"equipment"`
+	MovementPattern  string                         `json:"movement_pattern"`
+	Translations     map[string]ExerciseTranslation `json:"translations"`
+}
+
+// UpdateExerciseRequest represents the request body for updating a custom exercise
+type UpdateExerciseRequest struct {
+	Name             *string                        `json:"name"`
+	PrimaryMuscles   []string                       `json:"primary_muscles"`
+	SecondaryMuscles []string                       `json:"secondary_muscles"`
+	Equipment        *string                        `json:"equipment"`
+	MovementPattern  *string                        `json:"movement_pattern"`
+	Translations     map[string]ExerciseTranslation `json:"translations"`
+}
+
+// TableName overrides the table name
+func (Exercise) TableName() string {
+	return "exercises"
+}
+
+// IsCustom returns true if the exercise belongs to a trainer rather than the global library
+func (e Exercise) IsCustom() bool {
+	return e.TrainerID != nil
+}
+
+// Validate checks the muscle groups, equipment, movement pattern and translation languages
+// against the known values and returns a description of the first problem found
+func (e Exercise) Validate() string {
+	for _, muscle := range append(append([]string{}, e.PrimaryMuscles...), e.SecondaryMuscles...) {
+		if !contains(MuscleGroups, muscle) {
+			return "Invalid muscle group: " + muscle
+		}
+	}
+	if e.Equipment != "" && !contains(Equipment, e.Equipment) {
+		return "Invalid equipment: " + e.Equipment
+	}
+	if e.MovementPattern != "" && !contains(MovementPatterns, e.MovementPattern) {
+		return "Invalid movement pattern: " + e.MovementPattern
+	}
+	for lang := range e.Translations {
+		if lang != LanguageEnglish && lang != LanguageTurkish {
+			return "Unsupported translation language: " + lang
+		}
+	}
+	return ""
+}
+
+// contains returns true if values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
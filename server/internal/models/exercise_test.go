@@ -0,0 +1,60 @@
+package models
+
+import "testing"
+
+func TestExerciseValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		exercise Exercise
+		wantOK   bool
+	}{
+		{
+			name: "valid",
+			exercise: Exercise{
+				PrimaryMuscles:   []string{"quads", "glutes"},
+				SecondaryMuscles: []string{"lower_back"},
+				Equipment:        "barbell",
+				MovementPattern:  "squat",
+				Translations:     map[string]ExerciseTranslation{LanguageTurkish: {Name: "Squat"}},
+			},
+			wantOK: true,
+		},
+		{
+			name:     "nothing set",
+			exercise: Exercise{},
+			wantOK:   true,
+		},
+		{
+			name:     "unknown primary muscle",
+			exercise: Exercise{PrimaryMuscles: []string{"wings"}},
+		},
+		{
+			name:     "unknown secondary muscle",
+			exercise: Exercise{PrimaryMuscles: []string{"chest"}, SecondaryMuscles: []string{"pecs"}},
+		},
+		{
+			name:     "unknown equipment",
+			exercise: Exercise{Equipment: "rowing_boat"},
+		},
+		{
+			name:     "unknown movement pattern",
+			exercise: Exercise{MovementPattern: "jump"},
+		},
+		{
+			name:     "unsupported language",
+			exercise: Exercise{Translations: map[string]ExerciseTranslation{"de": {Name: "Kniebeuge"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := tt.exercise.Validate()
+			if tt.wantOK && problem != "" {
+				t.Errorf("Validate() = %q, want no problem", problem)
+			}
+			if !tt.wantOK && problem == "" {
+				t.Error("Validate() found no problem")
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GoalKind is what a goal measures
+type GoalKind string
+
+const (
+	GoalKindMeasurement GoalKind = "measurement" // a Measurement field, e.g. weight
+	GoalKindSessions    GoalKind = "sessions"    // completed sessions since the goal started
+	GoalKindCustom      GoalKind = "custom"      // a value the trainer updates by hand
+)
+
+// GoalStatus represents the status of a goal
+type GoalStatus string
+
+const (
+	GoalStatusActive    GoalStatus = "active"
+	GoalStatusAchieved  GoalStatus = "achieved"
+	GoalStatusMissed    GoalStatus = "missed"
+	GoalStatusAbandoned GoalStatus = "abandoned"
+)
+
+// Goal is a client's target for a measurement, a session count or a custom value.
+// Measurement goals are stored in metric and returned in the client's unit system.
+type Goal struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	Title         string         `gorm:"size:255;not null" json:"title"`
+	Kind          GoalKind       `gorm:"type:varchar(20);not null" json:"kind"`
+	Field         string         `gorm:"size:50" json:"field,omitempty"` // measurement field key for measurement goals
+	Unit          string         `gorm:"size:20" json:"unit"`            // stored for custom goals only
+	Baseline      float64        `gorm:"not null" json:"baseline"`
+	Target        float64        `gorm:"not null" json:"target"`
+	StartDate     time.Time      `gorm:"type:date;not null" json:"start_date"`
+	Deadline      time.Time      `gorm:"type:date;not null" json:"deadline"`
+	Status        GoalStatus     `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	AchievedAt    *time.Time     `json:"achieved_at,omitempty"`
+	CustomValue   *float64       `json:"-"` // latest value of a custom goal
+	CustomValueAt *time.Time     `json:"-"`
+	Notes         string         `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Calculated for responses and not stored
+	Current         *float64 `gorm:"-" json:"current"`
+	ProgressPercent *float64 `gorm:"-" json:"progress_percent"`
+	ProjectedValue  *float64 `gorm:"-" json:"projected_value"` // where the trend reaches by the deadline
+	OnTrack         *bool    `gorm:"-" json:"on_track"`        // set for active goals with enough history
+}
+
+// CreateGoalRequest represents the request body for creating a goal.
+// Values are in unit_system (defaults to the client's) for measurement goals.
+type CreateGoalRequest struct {
+	Title      string     `json:"title" binding:"required"`
+	Kind       GoalKind   `json:"kind" binding:"required,oneof=measurement sessions custom"`
+	Field      string     `json:"field"`
+	Unit       string     `json:"unit"`
+	Baseline   *float64   `json:"baseline"` // defaults to the latest measurement, or 0 for sessions
+	Target     *float64   `json:"target" binding:"required"`
+	StartDate  string     `json:"start_date"` // YYYY-MM-DD, defaults to today
+	Deadline   string     `json:"deadline" binding:"required"`
+	UnitSystem UnitSystem `json:"unit_system"`
+	Notes      string     `json:"notes"`
+}
+
+// UpdateGoalRequest represents the request body for updating a goal
+type UpdateGoalRequest struct {
+	Title       *string     `json:"title"`
+	Target      *float64    `json:"target"`
+	Deadline    *string     `json:"deadline"`
+	Status      *GoalStatus `json:"status"`  // only active or abandoned can be set by hand
+	CustomValue *float64    `json:"current"` // new value of a custom goal
+	UnitSystem  UnitSystem  `json:"unit_system"`
+	Notes       *string     `json:"notes"`
+}
+
+// TableName overrides the table name
+func (Goal) TableName() string {
+	return "goals"
+}
+
+// Increasing reports whether the goal is reached by going up, e.g. more sessions
+func (g *Goal) Increasing() bool {
+	return g.Target > g.Baseline
+}
+
+// Reached reports whether a value meets the goal's target
+func (g *Goal) Reached(value float64) bool {
+	if g.Increasing() {
+		return value >= g.Target
+	}
+	return value <= g.Target
+}
+
+// MeasurementField returns the measurement field of a measurement goal
+func (g *Goal) MeasurementField() (MeasurementField, bool) {
+	if g.Kind != GoalKindMeasurement {
+		return MeasurementField{}, false
+	}
+	return MeasurementFieldByKey(g.Field)
+}
+
+// Present converts a measurement goal's values from metric to the unit system,
+// rounds the values and sets the unit of every goal for a response
+func (g *Goal) Present(system UnitSystem) {
+	convert := roundUnit
+	if field, ok := g.MeasurementField(); ok {
+		convert = func(value float64) float64 {
+			return system.FromMetric(field.Dimension, value)
+		}
+		g.Unit = system.Unit(field.Dimension)
+	} else if g.Kind == GoalKindSessions {
+		g.Unit = "sessions"
+	}
+
+	g.Baseline = convert(g.Baseline)
+	g.Target = convert(g.Target)
+	if g.Current != nil {
+		current := convert(*g.Current)
+		g.Current = &current
+	}
+	if g.ProjectedValue != nil {
+		projected := convert(*g.ProjectedValue)
+		g.ProjectedValue = &projected
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestGoalReached(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline float64
+		target   float64
+		value    float64
+		want     bool
+	}{
+		{"losing weight, not yet", 90, 80, 82, false},
+		{"losing weight, on target", 90, 80, 80, true},
+		{"losing weight, past target", 90, 80, 79.5, true},
+		{"more sessions, not yet", 0, 12, 11, false},
+		{"more sessions, on target", 0, 12, 12, true},
+		{"gaining weight, went down", 60, 65, 59, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goal := Goal{Baseline: tt.baseline, Target: tt.target}
+			if got := goal.Reached(tt.value); got != tt.want {
+				t.Errorf("Reached(%v) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
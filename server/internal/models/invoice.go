@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceKind is the type of document issued
+type InvoiceKind string
+
+const (
+	InvoiceKindInvoice InvoiceKind = "invoice"
+	InvoiceKindReceipt InvoiceKind = "receipt"
+)
+
+// InvoiceItem is a single line on an invoice
+type InvoiceItem struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Total       float64 `json:"total"`
+}
+
+// Invoice is an issued invoice or receipt for a package purchase.
+// Seller and buyer details are copied at issue time so the document never changes.
+type Invoice struct {
+	ID        uuid.UUID   `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrainerID uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_invoices_trainer_sequence" json:"trainer_id"`
+	ClientID  uuid.UUID   `gorm:"type:uuid;not null;index" json:"client_id"`
+	PackageID uuid.UUID   `gorm:"type:uuid;not null;index" json:"package_id"`
+	Kind      InvoiceKind `gorm:"type:varchar(20);not null;default:'invoice'" json:"kind"`
+	Sequence  int         `gorm:"not null;uniqueIndex:idx_invoices_trainer_sequence" json:"sequence"`
+	Number    string      `gorm:"size:20;not null" json:"number"`
+	IssuedAt  time.Time   `gorm:"not null" json:"issued_at"`
+
+	SellerName      string `gorm:"size:255" json:"seller_name"`
+	SellerAddress   string `gorm:"type:text" json:"seller_address,omitempty"`
+	SellerPhone     string `gorm:"size:20" json:"seller_phone,omitempty"`
+	SellerEmail     string `gorm:"size:255" json:"seller_email,omitempty"`
+	SellerTaxOffice string `gorm:"size:100" json:"seller_tax_office,omitempty"`
+	SellerTaxNumber string `gorm:"size:20" json:"seller_tax_number,omitempty"`
+	BuyerName       string `gorm:"size:255" json:"buyer_name"`
+	BuyerEmail      string `gorm:"size:255" json:"buyer_email,omitempty"`
+	BuyerPhone      string `gorm:"size:20" json:"buyer_phone,omitempty"`
+
+	Items     []InvoiceItem `gorm:"serializer:json;type:jsonb" json:"items"`
+	Currency  string        `gorm:"size:3;not null" json:"currency"`
+	Subtotal  float64       `gorm:"type:numeric(10,2);not null" json:"subtotal"`
+	TaxRate   float64       `gorm:"type:numeric(5,2);not null;default:0" json:"tax_rate"`
+	TaxAmount float64       `gorm:"type:numeric(10,2);not null;default:0" json:"tax_amount"`
+	Total     float64       `gorm:"type:numeric(10,2);not null" json:"total"`
+	Notes     string        `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// InvoiceDocument holds the rendered PDF of an invoice
+type InvoiceDocument struct {
+	InvoiceID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Content   []byte    `gorm:"type:bytea;not null"`
+	CreatedAt time.Time
+}
+
+// CreateInvoiceRequest represents the request body for issuing an invoice for a package
+type CreateInvoiceRequest struct {
+	Kind InvoiceKind `json:"kind"`
+	// Price before tax; defaults to the package price
+	Price *float64 `json:"price" binding:"omitempty,min=0"`
+	// TaxRate is a percentage, e.g. 20 for 20% VAT
+	TaxRate  float64    `json:"tax_rate" binding:"min=0,max=100"`
+	IssuedAt *time.Time `json:"issued_at"`
+	Notes    string     `json:"notes"`
+}
+
+// TableName overrides the table name
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// TableName overrides the table name
+func (InvoiceDocument) TableName() string {
+	return "invoice_documents"
+}
+
+// InvoiceNumber formats a trainer's invoice sequence number
+func InvoiceNumber(sequence int) string {
+	return fmt.Sprintf("INV-%06d", sequence)
+}
+
+// Title returns the heading printed on the document
+func (i Invoice) Title() string {
+	if i.Kind == InvoiceKindReceipt {
+		return "RECEIPT"
+	}
+	return "INVOICE"
+}
@@ -30,8 +30,16 @@ type Measurement struct {
 	CreatedAt  time.Time      `json:"created_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// Calculated for responses and not stored
+	Derived    BodyComposition      `gorm:"-" json:"derived"`
+	UnitSystem UnitSystem           `gorm:"-" json:"unit_system"`
+	Values     map[string]UnitValue `gorm:"-" json:"values"` // the values with units, in unit_system
+
 	// Relationship
 	Client Client `gorm:"foreignKey:ClientID" json:"client,omitempty"`
+
+	// Values of the trainer's custom metrics, returned in values
+	CustomValues []CustomMetricValue `gorm:"foreignKey:MeasurementID" json:"-"`
 }
 
 // CreateMeasurementRequest represents the request body for creating a measurement
@@ -51,6 +59,12 @@ type CreateMeasurementRequest struct {
 	LeftLegCm  *float64  `json:"left_leg_cm"`
 	Notes      string    `json:"notes"`
 	MeasuredAt time.Time `json:"measured_at"`
+
+	// Values can be given instead of the _kg/_cm fields, in unit_system
+	// (defaults to the client's), e.g. {"weight": 185, "height": 73}.
+	// The trainer's custom metrics are given by key, in the metric's unit.
+	UnitSystem UnitSystem         `json:"unit_system"`
+	Values     map[string]float64 `json:"values"`
 }
 
 // TableName overrides the table name
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrendSmoothing is the weight of each new entry in the exponential moving
+// average trend line; lower values give a smoother line
+const TrendSmoothing = 0.3
+
+// Windows in days for the changes reported in measurement progress
+const (
+	ProgressShortWindowDays = 30
+	ProgressLongWindowDays  = 90
+)
+
+// MeasurementPoint is one entry of a measurement field with its trend value
+type MeasurementPoint struct {
+	MeasurementID uuid.UUID `json:"measurement_id"`
+	MeasuredAt    time.Time `json:"measured_at"`
+	Value         float64   `json:"value"`
+	Trend         float64   `json:"trend"` // exponential moving average up to this entry
+}
+
+// FieldProgress is the history of one measurement field and how it changed.
+// Changes over a window compare the latest entry with the last entry at least
+// that many days before it, and are null if the history is shorter.
+type FieldProgress struct {
+	Field               string             `json:"field"`
+	Unit                string             `json:"unit"`
+	Latest              float64            `json:"latest"`
+	Trend               float64            `json:"trend"`
+	ChangeSincePrevious *float64           `json:"change_since_previous"`
+	ChangeSinceFirst    *float64           `json:"change_since_first"`
+	Change30Days        *float64           `json:"change_30_days"`
+	Change90Days        *float64           `json:"change_90_days"`
+	Points              []MeasurementPoint `json:"points"`
+}
+
+// MeasurementProgressResponse is returned by the measurement progress endpoint
+type MeasurementProgressResponse struct {
+	UnitSystem UnitSystem      `json:"unit_system"`
+	Fields     []FieldProgress `json:"fields"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PackageStatus represents the stored state of a session package
+type PackageStatus string
+
+const (
+	PackageStatusActive    PackageStatus = "active"
+	PackageStatusFrozen    PackageStatus = "frozen"
+	PackageStatusCancelled PackageStatus = "cancelled"
+)
+
+// DefaultCurrency is used when a package or payment has no currency set
+const DefaultCurrency = "TRY"
+
+// Package represents a block of sessions a client purchased
+type Package struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	SessionCount int            `gorm:"not null" json:"session_count"`
+	Price        float64        `gorm:"type:numeric(10,2);not null;default:0" json:"price"`
+	Currency     string         `gorm:"size:3;not null;default:'TRY'" json:"currency"`
+	PurchasedAt  time.Time      `gorm:"not null" json:"purchased_at"`
+	StartsAt     time.Time      `gorm:"not null;index" json:"starts_at"`
+	ExpiresAt    *time.Time     `json:"expires_at,omitempty"`
+	Status       PackageStatus  `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	Notes        string         `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Freezes []PackageFreeze `gorm:"foreignKey:PackageID" json:"freezes,omitempty"`
+}
+
+// PackageFreeze is a period during which a package is paused
+// EndsAt is nil while the freeze is still open
+type PackageFreeze struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	PackageID uuid.UUID  `gorm:"type:uuid;not null;index" json:"package_id"`
+	StartsAt  time.Time  `gorm:"not null" json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	Reason    string     `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PackageResponse includes the package's usage for API responses
+type PackageResponse struct {
+	Package
+	UsedSessions      int  `json:"used_sessions"`
+	RemainingSessions int  `json:"remaining_sessions"`
+	Expired           bool `json:"expired"`
+}
+
+// CreatePackageRequest represents the request body for selling a package
+type CreatePackageRequest struct {
+	SessionCount int        `json:"session_count" binding:"required,min=1"`
+	Price        float64    `json:"price" binding:"min=0"`
+	Currency     string     `json:"currency"`
+	PurchasedAt  *time.Time `json:"purchased_at"`
+	StartsAt     *time.Time `json:"starts_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	// ValidityDays sets ExpiresAt relative to StartsAt when ExpiresAt is not given
+	ValidityDays *int   `json:"validity_days"`
+	Notes        string `json:"notes"`
+}
+
+// UpdatePackageRequest represents the request body for updating a package
+type UpdatePackageRequest struct {
+	SessionCount *int           `json:"session_count"`
+	Price        *float64       `json:"price"`
+	Currency     *string        `json:"currency"`
+	ExpiresAt    *time.Time     `json:"expires_at"`
+	Status       *PackageStatus `json:"status"`
+	Notes        *string        `json:"notes"`
+}
+
+// FreezePackageRequest represents the request body for freezing a package
+type FreezePackageRequest struct {
+	StartsAt *time.Time `json:"starts_at"`
+	Reason   string     `json:"reason"`
+}
+
+// TableName overrides the table name
+func (Package) TableName() string {
+	return "packages"
+}
+
+// TableName overrides the table name
+func (PackageFreeze) TableName() string {
+	return "package_freezes"
+}
+
+// IsExpired returns true if the package expired at the given time
+func (p Package) IsExpired(at time.Time) bool {
+	return p.ExpiresAt != nil && !at.Before(*p.ExpiresAt)
+}
+
+// CountsTowardBalance returns true if the package's unused sessions are still available to the client
+func (p Package) CountsTowardBalance(at time.Time) bool {
+	return p.Status != PackageStatusCancelled && !p.IsExpired(at)
+}
+
+// Unfreeze closes a freeze at the given time and pushes the expiry back by its length
+func (p *Package) Unfreeze(freeze *PackageFreeze, at time.Time) {
+	freeze.EndsAt = &at
+	if p.ExpiresAt != nil && at.After(freeze.StartsAt) {
+		extended := p.ExpiresAt.Add(at.Sub(freeze.StartsAt))
+		p.ExpiresAt = &extended
+	}
+	p.Status = PackageStatusActive
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPackageCountsTowardBalance(t *testing.T) {
+	now := time.Date(2026, time.January, 5, 12, 0, 0, 0, time.UTC)
+	earlier, later := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		pkg         Package
+		wantExpired bool
+		wantCounts  bool
+	}{
+		{"no expiry", Package{Status: PackageStatusActive}, false, true},
+		{"expires later", Package{Status: PackageStatusActive, ExpiresAt: &later}, false, true},
+		{"expires now", Package{Status: PackageStatusActive, ExpiresAt: &now}, true, false},
+		{"expired", Package{Status: PackageStatusActive, ExpiresAt: &earlier}, true, false},
+		{"frozen", Package{Status: PackageStatusFrozen, ExpiresAt: &later}, false, true},
+		{"cancelled", Package{Status: PackageStatusCancelled}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pkg.IsExpired(now); got != tt.wantExpired {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.wantExpired)
+			}
+			if got := tt.pkg.CountsTowardBalance(now); got != tt.wantCounts {
+				t.Errorf("CountsTowardBalance() = %v, want %v", got, tt.wantCounts)
+			}
+		})
+	}
+}
+
+func TestPackageUnfreeze(t *testing.T) {
+	start := time.Date(2026, time.January, 5, 12, 0, 0, 0, time.UTC)
+	expires := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		expiresAt   *time.Time
+		at          time.Time
+		wantExpires *time.Time
+	}{
+		{
+			name:        "pushes the expiry back by the freeze",
+			expiresAt:   &expires,
+			at:          start.AddDate(0, 0, 10),
+			wantExpires: ptrTime(expires.AddDate(0, 0, 10)),
+		},
+		{
+			name:        "closed as soon as it opened",
+			expiresAt:   &expires,
+			at:          start,
+			wantExpires: &expires,
+		},
+		{
+			name: "no expiry",
+			at:   start.AddDate(0, 0, 10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := Package{Status: PackageStatusFrozen, ExpiresAt: tt.expiresAt}
+			freeze := PackageFreeze{StartsAt: start}
+			pkg.Unfreeze(&freeze, tt.at)
+
+			if pkg.Status != PackageStatusActive {
+				t.Errorf("Status = %s, want %s", pkg.Status, PackageStatusActive)
+			}
+			if freeze.EndsAt == nil || !freeze.EndsAt.Equal(tt.at) {
+				t.Errorf("freeze EndsAt = %v, want %v", freeze.EndsAt, tt.at)
+			}
+			switch {
+			case tt.wantExpires == nil && pkg.ExpiresAt != nil:
+				t.Errorf("ExpiresAt = %v, want nil", *pkg.ExpiresAt)
+			case tt.wantExpires != nil && (pkg.ExpiresAt == nil || !pkg.ExpiresAt.Equal(*tt.wantExpires)):
+				t.Errorf("ExpiresAt = %v, want %v", pkg.ExpiresAt, *tt.wantExpires)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PaymentType distinguishes money received from money given back
+type PaymentType string
+
+const (
+	PaymentTypePayment PaymentType = "payment"
+	PaymentTypeRefund  PaymentType = "refund"
+)
+
+// PaymentMethod represents how a payment was made
+type PaymentMethod string
+
+const (
+	PaymentMethodCash     PaymentMethod = "cash"
+	PaymentMethodCard     PaymentMethod = "card"
+	PaymentMethodTransfer PaymentMethod = "transfer"
+)
+
+// Payment represents money received from (or refunded to) a client
+type Payment struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID  uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	PackageID *uuid.UUID     `gorm:"type:uuid;index" json:"package_id,omitempty"`
+	Type      PaymentType    `gorm:"type:varchar(20);not null;default:'payment'" json:"type"`
+	Amount    float64        `gorm:"type:numeric(10,2);not null" json:"amount"`
+	Currency  string         `gorm:"size:3;not null;default:'TRY'" json:"currency"`
+	Method    PaymentMethod  `gorm:"type:varchar(20);not null" json:"method"`
+	PaidAt    time.Time      `gorm:"not null;index" json:"paid_at"`
+	Notes     string         `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// PackageInstallment is a scheduled part of a package's price
+type PackageInstallment struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	PackageID uuid.UUID `gorm:"type:uuid;not null;index" json:"package_id"`
+	DueAt     time.Time `gorm:"not null" json:"due_at"`
+	Amount    float64   `gorm:"type:numeric(10,2);not null" json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreatePaymentRequest represents the request body for recording a payment or refund
+type CreatePaymentRequest struct {
+	PackageID *uuid.UUID    `json:"package_id"`
+	Type      PaymentType   `json:"type"`
+	Amount    float64       `json:"amount" binding:"required,gt=0"`
+	Currency  string        `json:"currency"`
+	Method    PaymentMethod `json:"method" binding:"required"`
+	PaidAt    *time.Time    `json:"paid_at"`
+	Notes     string        `json:"notes"`
+}
+
+// InstallmentEntry is a single installment in a payment plan
+type InstallmentEntry struct {
+	DueAt  time.Time `json:"due_at" binding:"required"`
+	Amount float64   `json:"amount" binding:"required,gt=0"`
+}
+
+// SetInstallmentsRequest replaces a package's payment plan
+// The amounts must add up to the package price; an empty list removes the plan
+type SetInstallmentsRequest struct {
+	Installments []InstallmentEntry `json:"installments" binding:"dive"`
+}
+
+// CurrencyBalance is a client's account in a single currency
+type CurrencyBalance struct {
+	Currency string  `json:"currency"`
+	Charged  float64 `json:"charged"`
+	Paid     float64 `json:"paid"`
+	Refunded float64 `json:"refunded"`
+	// Outstanding is negative when the client is in credit
+	Outstanding float64 `json:"outstanding"`
+	// Overdue is the part of Outstanding that was already due
+	Overdue float64 `json:"overdue"`
+}
+
+// ClientBalanceResponse is a client's balance in every currency they were charged or paid in
+type ClientBalanceResponse struct {
+	ClientID  uuid.UUID         `json:"client_id"`
+	FirstName string            `json:"first_name"`
+	LastName  string            `json:"last_name"`
+	Balances  []CurrencyBalance `json:"balances"`
+}
+
+// TableName overrides the table name
+func (Payment) TableName() string {
+	return "payments"
+}
+
+// TableName overrides the table name
+func (PackageInstallment) TableName() string {
+	return "package_installments"
+}
+
+// IsValidPaymentMethod checks if a payment method is valid
+func IsValidPaymentMethod(method PaymentMethod) bool {
+	switch method {
+	case PaymentMethodCash, PaymentMethodCard, PaymentMethodTransfer:
+		return true
+	}
+	return false
+}
+
+// IsValidPaymentType checks if a payment type is valid
+func IsValidPaymentType(paymentType PaymentType) bool {
+	return paymentType == PaymentTypePayment || paymentType == PaymentTypeRefund
+}
+
+// HasOutstanding returns true if any currency has money owed
+func (r ClientBalanceResponse) HasOutstanding() bool {
+	for _, b := range r.Balances {
+		if b.Outstanding > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOverdue returns true if any currency has money past its due date
+func (r ClientBalanceResponse) HasOverdue() bool {
+	for _, b := range r.Balances {
+		if b.Overdue > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Program is a multi-week training program. Programs without a client are
+// reusable templates; assigning a template to a client creates a copy.
+type Program struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrainerID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"trainer_id"`
+	ClientID    *uuid.UUID     `gorm:"type:uuid;index" json:"client_id,omitempty"`   // nil for templates
+	TemplateID  *uuid.UUID     `gorm:"type:uuid;index" json:"template_id,omitempty"` // template the program was copied from
+	Name        string         `gorm:"size:255;not null" json:"name"`
+	Description string         `gorm:"type:text" json:"description,omitempty"`
+	StartsOn    *time.Time     `gorm:"type:date" json:"starts_on,omitempty"` // first day of week 1 for assigned programs
+	Weeks       []ProgramWeek  `gorm:"foreignKey:ProgramID;constraint:OnDelete:CASCADE" json:"weeks"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// ProgramWeek is a numbered week of a program
+type ProgramWeek struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ProgramID uuid.UUID    `gorm:"type:uuid;not null;index" json:"-"`
+	Number    int          `gorm:"not null" json:"number"`
+	Notes     string       `gorm:"type:text" json:"notes,omitempty"`
+	Days      []ProgramDay `gorm:"foreignKey:ProgramWeekID;constraint:OnDelete:CASCADE" json:"days"`
+}
+
+// ProgramDay is a training day within a program week
+type ProgramDay struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ProgramWeekID uuid.UUID         `gorm:"type:uuid;not null;index" json:"-"`
+	Day           int               `gorm:"not null" json:"day"` // 1-7, counted from the start of the week
+	Name          string            `gorm:"size:255" json:"name,omitempty"`
+	Notes         string            `gorm:"type:text" json:"notes,omitempty"`
+	Date          *time.Time        `gorm:"-" json:"date,omitempty"` // calendar date for assigned programs
+	Exercises     []ProgramExercise `gorm:"foreignKey:ProgramDayID;constraint:OnDelete:CASCADE" json:"exercises"`
+}
+
+// ProgramExercise is an exercise prescribed on a program day
+type ProgramExercise struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ProgramDayID uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	ExerciseID   *uuid.UUID `gorm:"type:uuid;index" json:"exercise_id,omitempty"`
+	Position     int        `gorm:"not null" json:"position"`
+	Name         string     `gorm:"size:255;not null" json:"name"`
+	Sets         int        `gorm:"not null" json:"sets"`
+	RepsMin      *int       `json:"reps_min,omitempty"`
+	RepsMax      *int       `json:"reps_max,omitempty"`
+	TargetRPE    *float64   `gorm:"type:numeric(3,1)" json:"target_rpe,omitempty"`
+	PercentOneRM *float64   `gorm:"type:numeric(5,2)" json:"percent_one_rm,omitempty"` // intensity as % of one-rep max
+	RestSeconds  *int       `json:"rest_seconds,omitempty"`
+	Tempo        string     `gorm:"size:20" json:"tempo,omitempty"`
+	Notes        string     `gorm:"type:text" json:"notes,omitempty"`
+}
+
+// SaveProgramRequest represents the request body for creating a program or replacing its contents.
+// Weeks are numbered in the order given.
+type SaveProgramRequest struct {
+	Name        string             `json:"name" binding:"required"`
+	Description string             `json:"description"`
+	Weeks       []ProgramWeekInput `json:"weeks" binding:"dive"`
+}
+
+// ProgramWeekInput is a week in a SaveProgramRequest
+type ProgramWeekInput struct {
+	Notes string            `json:"notes"`
+	Days  []ProgramDayInput `json:"days" binding:"dive"`
+}
+
+// ProgramDayInput is a training day in a SaveProgramRequest
+type ProgramDayInput struct {
+	Day       int                    `json:"day" binding:"required,min=1,max=7"`
+	Name      string                 `json:"name"`
+	Notes     string                 `json:"notes"`
+	Exercises []ProgramExerciseInput `json:"exercises" binding:"dive"`
+}
+
+// ProgramExerciseInput is a prescribed exercise in a SaveProgramRequest.
+// Name defaults to the library exercise's name.
+type ProgramExerciseInput struct {
+	ExerciseID   *uuid.UUID `json:"exercise_id"`
+	Name         string     `json:"name" binding:"required_without=ExerciseID"`
+	Sets         int        `json:"sets" binding:"required,min=1"`
+	RepsMin      *int       `json:"reps_min" binding:"omitempty,min=0"`
+	RepsMax      *int       `json:"reps_max" binding:"omitempty,min=0"`
+	TargetRPE    *float64   `json:"target_rpe" binding:"omitempty,min=1,max=10"`
+	PercentOneRM *float64   `json:"percent_one_rm" binding:"omitempty,min=0,max=150"`
+	RestSeconds  *int       `json:"rest_seconds" binding:"omitempty,min=0"`
+	Tempo        string     `json:"tempo" binding:"max=20"`
+	Notes        string     `json:"notes"`
+}
+
+// AssignProgramRequest represents the request body for assigning a template to a client
+type AssignProgramRequest struct {
+	ClientID uuid.UUID `json:"client_id" binding:"required"`
+	StartsOn string    `json:"starts_on" binding:"required"` // YYYY-MM-DD
+}
+
+// TableName overrides the table name
+func (Program) TableName() string {
+	return "programs"
+}
+
+// TableName overrides the table name
+func (ProgramWeek) TableName() string {
+	return "program_weeks"
+}
+
+// TableName overrides the table name
+func (ProgramDay) TableName() string {
+	return "program_days"
+}
+
+// TableName overrides the table name
+func (ProgramExercise) TableName() string {
+	return "program_exercises"
+}
+
+// IsTemplate returns true if the program is a reusable template rather than assigned to a client
+func (p Program) IsTemplate() bool {
+	return p.ClientID == nil
+}
+
+// ProgramWeeks converts the request into numbered weeks, days and exercises
+func (r SaveProgramRequest) ProgramWeeks() []ProgramWeek {
+	weeks := make([]ProgramWeek, 0, len(r.Weeks))
+	for i, w := range r.Weeks {
+		week := ProgramWeek{Number: i + 1, Notes: w.Notes, Days: make([]ProgramDay, 0, len(w.Days))}
+		for _, d := range w.Days {
+			day := ProgramDay{Day: d.Day, Name: d.Name, Notes: d.Notes, Exercises: make([]ProgramExercise, 0, len(d.Exercises))}
+			for k, e := range d.Exercises {
+				day.Exercises = append(day.Exercises, ProgramExercise{
+					ExerciseID:   e.ExerciseID,
+					Position:     k + 1,
+					Name:         e.Name,
+					Sets:         e.Sets,
+					RepsMin:      e.RepsMin,
+					RepsMax:      e.RepsMax,
+					TargetRPE:    e.TargetRPE,
+					PercentOneRM: e.PercentOneRM,
+					RestSeconds:  e.RestSeconds,
+					Tempo:        e.Tempo,
+					Notes:        e.Notes,
+				})
+			}
+			week.Days = append(week.Days, day)
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// Validate checks that rep ranges are ordered and no week has two days with the
+// same number, and returns a description of the first problem found
+func (r SaveProgramRequest) Validate() string {
+	for i, w := range r.Weeks {
+		seen := map[int]bool{}
+		for _, d := range w.Days {
+			if seen[d.Day] {
+				return fmt.Sprintf("Week %d has more than one day %d", i+1, d.Day)
+			}
+			seen[d.Day] = true
+			for _, e := range d.Exercises {
+				if e.RepsMin != nil && e.RepsMax != nil && *e.RepsMin > *e.RepsMax {
+					return "reps_min cannot be greater than reps_max"
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// CopyStructure returns the program's weeks, days and exercises without their
+// IDs, ready to be created under another program
+func (p Program) CopyStructure() []ProgramWeek {
+	weeks := make([]ProgramWeek, 0, len(p.Weeks))
+	for _, w := range p.Weeks {
+		week := ProgramWeek{Number: w.Number, Notes: w.Notes, Days: make([]ProgramDay, 0, len(w.Days))}
+		for _, d := range w.Days {
+			day := ProgramDay{Day: d.Day, Name: d.Name, Notes: d.Notes, Exercises: make([]ProgramExercise, 0, len(d.Exercises))}
+			for _, e := range d.Exercises {
+				e.ID = uuid.Nil
+				e.ProgramDayID = uuid.Nil
+				day.Exercises = append(day.Exercises, e)
+			}
+			week.Days = append(week.Days, day)
+		}
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// FillDates sets the calendar date of each day of an assigned program
+func (p *Program) FillDates() {
+	if p.StartsOn == nil {
+		return
+	}
+	for i := range p.Weeks {
+		for j := range p.Weeks[i].Days {
+			date := p.StartsOn.AddDate(0, 0, (p.Weeks[i].Number-1)*7+p.Weeks[i].Days[j].Day-1)
+			p.Weeks[i].Days[j].Date = &date
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSaveProgramRequestValidate(t *testing.T) {
+	reps := func(n int) *int { return &n }
+
+	tests := []struct {
+		name   string
+		weeks  []ProgramWeekInput
+		wantOK bool
+	}{
+		{
+			name: "valid",
+			weeks: []ProgramWeekInput{
+				{Days: []ProgramDayInput{
+					{Day: 1, Exercises: []ProgramExerciseInput{{Name: "Squat", Sets: 3, RepsMin: reps(6), RepsMax: reps(8)}}},
+					{Day: 3},
+				}},
+				{Days: []ProgramDayInput{{Day: 1}}},
+			},
+			wantOK: true,
+		},
+		{
+			name:  "same day twice in a week",
+			weeks: []ProgramWeekInput{{Days: []ProgramDayInput{{Day: 2}, {Day: 2}}}},
+		},
+		{
+			name: "reversed rep range",
+			weeks: []ProgramWeekInput{{Days: []ProgramDayInput{
+				{Day: 1, Exercises: []ProgramExerciseInput{{Name: "Squat", Sets: 3, RepsMin: reps(10), RepsMax: reps(8)}}},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := SaveProgramRequest{Name: "Block", Weeks: tt.weeks}.Validate()
+			if tt.wantOK && problem != "" {
+				t.Errorf("Validate() = %q, want no problem", problem)
+			}
+			if !tt.wantOK && problem == "" {
+				t.Error("Validate() found no problem")
+			}
+		})
+	}
+}
+
+func TestProgramWeeksAndCopyStructure(t *testing.T) {
+	req := SaveProgramRequest{Weeks: []ProgramWeekInput{
+		{Days: []ProgramDayInput{{Day: 1, Exercises: []ProgramExerciseInput{{Name: "Squat", Sets: 3}, {Name: "Bench", Sets: 3}}}}},
+		{Days: []ProgramDayInput{{Day: 4}}},
+	}}
+
+	weeks := req.ProgramWeeks()
+	if len(weeks) != 2 || weeks[0].Number != 1 || weeks[1].Number != 2 {
+		t.Fatalf("weeks are not numbered in order: %+v", weeks)
+	}
+	exercises := weeks[0].Days[0].Exercises
+	if exercises[0].Position != 1 || exercises[1].Position != 2 {
+		t.Errorf("exercises are not numbered in order: %+v", exercises)
+	}
+
+	weeks[0].Days[0].Exercises[0].ID = uuid.New()
+	weeks[0].Days[0].Exercises[0].ProgramDayID = uuid.New()
+	copied := Program{Weeks: weeks}.CopyStructure()
+	if e := copied[0].Days[0].Exercises[0]; e.ID != uuid.Nil || e.ProgramDayID != uuid.Nil || e.Name != "Squat" {
+		t.Errorf("copied exercise = %+v, want the exercise without IDs", e)
+	}
+}
+
+func TestProgramFillDates(t *testing.T) {
+	startsOn := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	program := Program{
+		StartsOn: &startsOn,
+		Weeks: []ProgramWeek{
+			{Number: 1, Days: []ProgramDay{{Day: 1}, {Day: 5}}},
+			{Number: 3, Days: []ProgramDay{{Day: 2}}},
+		},
+	}
+	program.FillDates()
+
+	want := []string{"2026-01-05", "2026-01-09", "2026-01-20"}
+	var got []string
+	for _, w := range program.Weeks {
+		for _, d := range w.Days {
+			if d.Date == nil {
+				t.Fatalf("week %d day %d has no date", w.Number, d.Day)
+			}
+			got = append(got, d.Date.Format("2006-01-02"))
+		}
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("date %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	template := Program{Weeks: []ProgramWeek{{Number: 1, Days: []ProgramDay{{Day: 1}}}}}
+	template.FillDates()
+	if template.Weeks[0].Days[0].Date != nil {
+		t.Error("FillDates() set a date on a template")
+	}
+}
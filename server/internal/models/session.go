@@ -11,26 +11,39 @@ import (
 type SessionStatus string
 
 const (
+	SessionStatusPending   SessionStatus = "pending" // requested by the client, awaiting trainer approval
 	SessionStatusScheduled SessionStatus = "scheduled"
 	SessionStatusCompleted SessionStatus = "completed"
 	SessionStatusNoShow    SessionStatus = "no_show"
 	SessionStatusCancelled SessionStatus = "cancelled"
+	// Cancelled inside the trainer's late-cancellation window; counts as used
+	SessionStatusLateCancelled SessionStatus = "late_cancelled"
+	SessionStatusRejected      SessionStatus = "rejected" // booking request declined by the trainer
 )
 
 // Session represents a training session
 type Session struct {
 	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	ClientID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	SeriesID        *uuid.UUID     `gorm:"type:uuid;index" json:"series_id,omitempty"`
+	PackageID       *uuid.UUID     `gorm:"type:uuid;index" json:"package_id,omitempty"`     // package a used session was taken from
+	ProgramDayID    *uuid.UUID     `gorm:"type:uuid;index" json:"program_day_id,omitempty"` // day of the client's program on this date
 	ScheduledAt     time.Time      `gorm:"not null;index" json:"scheduled_at"`
 	DurationMinutes int            `gorm:"not null;default:60" json:"duration_minutes"`
 	Status          SessionStatus  `gorm:"type:varchar(20);not null;default:'scheduled'" json:"status"`
 	Notes           string         `gorm:"type:text" json:"notes,omitempty"`
+	RPE             *int           `gorm:"type:smallint" json:"rpe,omitempty"`                   // session RPE (1-10), recorded once completed
+	NeedsAttendance bool           `gorm:"not null;default:false;index" json:"needs_attendance"` // set by the no-show worker
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
-	// Relationship
-	Client Client `gorm:"foreignKey:ClientID" json:"client,omitempty"`
+	// Warnings are returned with the session but not stored (e.g. outside working hours)
+	Warnings []string `gorm:"-" json:"warnings,omitempty"`
+
+	// Relationships
+	Client     Client      `gorm:"foreignKey:ClientID" json:"client,omitempty"`
+	WorkoutLog *WorkoutLog `gorm:"foreignKey:SessionID" json:"workout_log,omitempty"`
 }
 
 // CreateSessionRequest represents the request body for creating a session
@@ -39,6 +52,8 @@ type CreateSessionRequest struct {
 	ScheduledAt     time.Time `json:"scheduled_at" binding:"required"`
 	DurationMinutes int       `json:"duration_minutes"`
 	Notes           string    `json:"notes"`
+	// AllowOverlap books the session even if it overlaps another (semi-private sessions)
+	AllowOverlap bool `json:"allow_overlap"`
 }
 
 // UpdateSessionRequest represents the request body for updating a session
@@ -47,11 +62,65 @@ type UpdateSessionRequest struct {
 	DurationMinutes *int           `json:"duration_minutes"`
 	Status          *SessionStatus `json:"status"`
 	Notes           *string        `json:"notes"`
+	// RPE is the client's rating of the whole session; it only applies to this session
+	RPE *int `json:"rpe" binding:"omitempty,min=1,max=10"`
+	// Scope applies the edit to other occurrences when the session belongs to a series
+	Scope        EditScope `json:"scope"`
+	AllowOverlap bool      `json:"allow_overlap"`
+	// Reason is recorded in the status history when the status changes
+	Reason string `json:"reason"`
 }
 
 // UpdateStatusRequest represents the request body for updating session status
 type UpdateStatusRequest struct {
 	Status SessionStatus `json:"status" binding:"required"`
+	Scope  EditScope     `json:"scope"`
+	Reason string        `json:"reason"`
+}
+
+// BookingRequest represents a client's public request for a session
+// The client is identified by the email (and phone, if on file) the trainer has for them
+type BookingRequest struct {
+	Email       string    `json:"email" binding:"required,email"`
+	Phone       string    `json:"phone"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+	Notes       string    `json:"notes"`
+}
+
+// BookingResponse is returned to the client after a booking request
+type BookingResponse struct {
+	ID              uuid.UUID     `json:"id"`
+	ScheduledAt     time.Time     `json:"scheduled_at"`
+	DurationMinutes int           `json:"duration_minutes"`
+	Status          SessionStatus `json:"status"`
+}
+
+// RejectBookingRequest represents the request body for rejecting a booking request
+type RejectBookingRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ConflictCheckRequest represents the request body for a dry-run overlap check
+type ConflictCheckRequest struct {
+	ScheduledAt      time.Time  `json:"scheduled_at" binding:"required"`
+	DurationMinutes  int        `json:"duration_minutes"`
+	ExcludeSessionID *uuid.UUID `json:"exclude_session_id"`
+}
+
+// SessionConflict describes an existing session that overlaps a requested slot
+type SessionConflict struct {
+	SessionID   uuid.UUID     `json:"session_id"`
+	ClientID    uuid.UUID     `json:"client_id"`
+	ClientName  string        `json:"client_name"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	EndsAt      time.Time     `json:"ends_at"`
+	Status      SessionStatus `json:"status"`
+}
+
+// ConflictCheckResponse is returned by the dry-run overlap check
+type ConflictCheckResponse struct {
+	HasConflicts bool              `json:"has_conflicts"`
+	Conflicts    []SessionConflict `json:"conflicts"`
 }
 
 // TableName overrides the table name
@@ -60,18 +129,90 @@ func (Session) TableName() string {
 }
 
 // IsUsed returns true if the session counts as "used" from the package
-// Completed and NoShow both count as used sessions
+// Completed, NoShow and LateCancelled all count as used sessions
 func (s Session) IsUsed() bool {
-	return s.Status == SessionStatusCompleted || s.Status == SessionStatusNoShow
+	for _, status := range UsedStatuses() {
+		if s.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// UsedStatuses returns the statuses that consume a session from the package
+func UsedStatuses() []SessionStatus {
+	return []SessionStatus{
+		SessionStatusCompleted,
+		SessionStatusNoShow,
+		SessionStatusLateCancelled,
+	}
+}
+
+// BookedStatuses returns the statuses that hold a session from the package,
+// either already used or still upcoming
+func BookedStatuses() []SessionStatus {
+	return append([]SessionStatus{
+		SessionStatusPending,
+		SessionStatusScheduled,
+	}, UsedStatuses()...)
+}
+
+// ClassifyCancellation returns the status a cancellation should get. Scheduled
+// sessions cancelled less than windowHours before they start are late
+// cancellations; a window of 0 disables the policy.
+func (s Session) ClassifyCancellation(windowHours int, now time.Time) SessionStatus {
+	if windowHours <= 0 || s.Status != SessionStatusScheduled {
+		return SessionStatusCancelled
+	}
+	if now.After(s.ScheduledAt.Add(-time.Duration(windowHours) * time.Hour)) {
+		return SessionStatusLateCancelled
+	}
+	return SessionStatusCancelled
+}
+
+// BeforeSave clears the needs-attendance flag once attendance is recorded or
+// the session is moved back into the future
+func (s *Session) BeforeSave(tx *gorm.DB) error {
+	if s.Status != SessionStatusScheduled || s.EndsAt().After(time.Now()) {
+		s.NeedsAttendance = false
+	}
+	return nil
+}
+
+// EndsAt returns when the session finishes
+func (s Session) EndsAt() time.Time {
+	return s.ScheduledAt.Add(time.Duration(s.DurationMinutes) * time.Minute)
+}
+
+// FreeSlotStatuses returns the statuses whose sessions no longer occupy a time slot
+func FreeSlotStatuses() []SessionStatus {
+	return []SessionStatus{
+		SessionStatusCancelled,
+		SessionStatusLateCancelled,
+		SessionStatusRejected,
+	}
+}
+
+// BlocksSlot returns true if a session with this status occupies its time slot
+func BlocksSlot(status SessionStatus) bool {
+	for _, s := range FreeSlotStatuses() {
+		if s == status {
+			return false
+		}
+	}
+	return true
 }
 
 // ValidStatuses returns all valid session statuses
 func ValidStatuses() []SessionStatus {
 	return []SessionStatus{
+		SessionStatusPending,
 		SessionStatusScheduled,
 		SessionStatusCompleted,
 		SessionStatusNoShow,
 		SessionStatusCancelled,
+		SessionStatusLateCancelled,
+		SessionStatusRejected,
 	}
 }
 
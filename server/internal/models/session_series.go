@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EditScope controls which occurrences of a series an edit applies to
+type EditScope string
+
+const (
+	EditScopeThis      EditScope = "this"
+	EditScopeFollowing EditScope = "following"
+	EditScopeAll       EditScope = "all"
+)
+
+// IsValidEditScope checks if a scope is valid (empty means "this")
+func IsValidEditScope(scope EditScope) bool {
+	switch scope {
+	case "", EditScopeThis, EditScopeFollowing, EditScopeAll:
+		return true
+	}
+	return false
+}
+
+// SessionSeries represents a recurring set of sessions defined by an RRULE
+// The series is expanded into concrete Session rows when it is created
+type SessionSeries struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	StartsAt        time.Time      `gorm:"not null" json:"starts_at"` // DTSTART
+	DurationMinutes int            `gorm:"not null;default:60" json:"duration_minutes"`
+	RRule           string         `gorm:"column:rrule;size:255;not null" json:"rrule"`
+	ExDates         []time.Time    `gorm:"column:exdates;type:text;serializer:json" json:"exdates"`
+	Notes           string         `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Client   Client    `gorm:"foreignKey:ClientID" json:"client,omitempty"`
+	Sessions []Session `gorm:"foreignKey:SeriesID" json:"sessions,omitempty"`
+}
+
+// SessionSeriesResponse includes expansion details for API responses
+type SessionSeriesResponse struct {
+	SessionSeries
+	// Truncated is true when expansion stopped early because the client's package ran out
+	Truncated bool     `json:"truncated"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+// CreateSessionSeriesRequest represents the request body for creating a session series
+type CreateSessionSeriesRequest struct {
+	ClientID        uuid.UUID   `json:"client_id" binding:"required"`
+	StartsAt        time.Time   `json:"starts_at" binding:"required"`
+	DurationMinutes int         `json:"duration_minutes"`
+	RRule           string      `json:"rrule" binding:"required"`
+	ExDates         []time.Time `json:"exdates"`
+	Notes           string      `json:"notes"`
+	AllowOverlap    bool        `json:"allow_overlap"`
+}
+
+// TableName overrides the table name
+func (SessionSeries) TableName() string {
+	return "session_series"
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actor types for session status changes
+const (
+	ActorTrainer = "trainer"
+	ActorClient  = "client"
+	ActorSystem  = "system"
+)
+
+// StatusActor identifies who changed a session's status
+type StatusActor struct {
+	Type string
+	ID   *uuid.UUID
+}
+
+// TrainerActor returns the actor for a change made by a trainer
+func TrainerActor(trainerID uuid.UUID) StatusActor {
+	return StatusActor{Type: ActorTrainer, ID: &trainerID}
+}
+
+// ClientActor returns the actor for a change made by a client
+func ClientActor(clientID uuid.UUID) StatusActor {
+	return StatusActor{Type: ActorClient, ID: &clientID}
+}
+
+// SystemActor returns the actor for a change made by a background job
+func SystemActor() StatusActor {
+	return StatusActor{Type: ActorSystem}
+}
+
+// SessionStatusDeleted is not a session status; it is the ToStatus of the event
+// that records a session being deleted, so the status history has no gap
+const SessionStatusDeleted SessionStatus = "deleted"
+
+// SessionStatusEvent records a single status transition of a session
+// FromStatus is empty for the event that records the session's creation
+type SessionStatusEvent struct {
+	ID         uuid.UUID     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	SessionID  uuid.UUID     `gorm:"type:uuid;not null;index" json:"session_id"`
+	FromStatus SessionStatus `gorm:"type:varchar(20)" json:"from_status,omitempty"`
+	ToStatus   SessionStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	ActorType  string        `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *uuid.UUID    `gorm:"type:uuid" json:"actor_id,omitempty"`
+	Reason     string        `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt  time.Time     `gorm:"not null;index" json:"created_at"`
+}
+
+// NewStatusEvent builds the event for a session that moved from the given status
+// to its current status
+func NewStatusEvent(session Session, from SessionStatus, actor StatusActor, reason string) SessionStatusEvent {
+	return SessionStatusEvent{
+		SessionID:  session.ID,
+		FromStatus: from,
+		ToStatus:   session.Status,
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		Reason:     reason,
+	}
+}
+
+// TableName overrides the table name
+func (SessionStatusEvent) TableName() string {
+	return "session_status_events"
+}
+
+// sessionTransitions is the allowed status transition graph
+var sessionTransitions = map[SessionStatus][]SessionStatus{
+	SessionStatusPending: {
+		SessionStatusScheduled,
+		SessionStatusRejected,
+		SessionStatusCancelled,
+	},
+	SessionStatusScheduled: {
+		SessionStatusCompleted,
+		SessionStatusNoShow,
+		SessionStatusCancelled,
+		SessionStatusLateCancelled,
+	},
+	// Attendance corrections
+	SessionStatusCompleted: {SessionStatusNoShow},
+	SessionStatusNoShow:    {SessionStatusCompleted},
+	// Waiving a late-cancellation charge
+	SessionStatusLateCancelled: {SessionStatusCancelled},
+}
+
+// InvalidTransitionError is returned for a status change the graph does not allow
+type InvalidTransitionError struct {
+	From SessionStatus
+	To   SessionStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change session status from %s to %s", e.From, e.To)
+}
+
+// AllowedTransitions returns the statuses a session can move to from the given status
+func AllowedTransitions(from SessionStatus) []SessionStatus {
+	return sessionTransitions[from]
+}
+
+// ValidateTransition checks a status change against the transition graph
+// Keeping the same status is always allowed
+func ValidateTransition(from, to SessionStatus) error {
+	if from == to {
+		return nil
+	}
+	for _, allowed := range sessionTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &InvalidTransitionError{From: from, To: to}
+}
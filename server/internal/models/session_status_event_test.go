@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from SessionStatus
+		to   SessionStatus
+		want bool
+	}{
+		{SessionStatusScheduled, SessionStatusScheduled, true},
+		{SessionStatusPending, SessionStatusScheduled, true},
+		{SessionStatusPending, SessionStatusRejected, true},
+		{SessionStatusPending, SessionStatusCancelled, true},
+		{SessionStatusPending, SessionStatusCompleted, false},
+		{SessionStatusScheduled, SessionStatusCompleted, true},
+		{SessionStatusScheduled, SessionStatusNoShow, true},
+		{SessionStatusScheduled, SessionStatusCancelled, true},
+		{SessionStatusScheduled, SessionStatusLateCancelled, true},
+		{SessionStatusScheduled, SessionStatusPending, false},
+		{SessionStatusCompleted, SessionStatusNoShow, true},
+		{SessionStatusNoShow, SessionStatusCompleted, true},
+		{SessionStatusCompleted, SessionStatusScheduled, false},
+		{SessionStatusLateCancelled, SessionStatusCancelled, true},
+		{SessionStatusCancelled, SessionStatusScheduled, false},
+		{SessionStatusCancelled, SessionStatusLateCancelled, false},
+		{SessionStatusRejected, SessionStatusScheduled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"_to_"+string(tt.to), func(t *testing.T) {
+			err := ValidateTransition(tt.from, tt.to)
+			if tt.want && err != nil {
+				t.Errorf("ValidateTransition() = %v, want nil", err)
+			}
+			if !tt.want {
+				var invalid *InvalidTransitionError
+				if !errors.As(err, &invalid) {
+					t.Fatalf("ValidateTransition() = %v, want an InvalidTransitionError", err)
+				}
+				if invalid.From != tt.from || invalid.To != tt.to {
+					t.Errorf("error is for %s to %s, want %s to %s", invalid.From, invalid.To, tt.from, tt.to)
+				}
+			}
+		})
+	}
+}
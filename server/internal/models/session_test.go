@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlocksSlot(t *testing.T) {
+	tests := []struct {
+		status SessionStatus
+		want   bool
+	}{
+		{SessionStatusPending, true},
+		{SessionStatusScheduled, true},
+		{SessionStatusCompleted, true},
+		{SessionStatusNoShow, true},
+		{SessionStatusCancelled, false},
+		{SessionStatusLateCancelled, false},
+		{SessionStatusRejected, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := BlocksSlot(tt.status); got != tt.want {
+				t.Errorf("BlocksSlot(%s) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyCancellation(t *testing.T) {
+	now := time.Date(2026, time.January, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		status      SessionStatus
+		scheduledAt time.Time
+		window      int
+		want        SessionStatus
+	}{
+		{
+			name:        "outside the window",
+			status:      SessionStatusScheduled,
+			scheduledAt: now.Add(48 * time.Hour),
+			window:      24,
+			want:        SessionStatusCancelled,
+		},
+		{
+			name:        "inside the window",
+			status:      SessionStatusScheduled,
+			scheduledAt: now.Add(23 * time.Hour),
+			window:      24,
+			want:        SessionStatusLateCancelled,
+		},
+		{
+			name:        "exactly at the window edge",
+			status:      SessionStatusScheduled,
+			scheduledAt: now.Add(24 * time.Hour),
+			window:      24,
+			want:        SessionStatusCancelled,
+		},
+		{
+			name:        "after the session started",
+			status:      SessionStatusScheduled,
+			scheduledAt: now.Add(-time.Hour),
+			window:      24,
+			want:        SessionStatusLateCancelled,
+		},
+		{
+			name:        "policy disabled",
+			status:      SessionStatusScheduled,
+			scheduledAt: now.Add(time.Hour),
+			window:      0,
+			want:        SessionStatusCancelled,
+		},
+		{
+			name:        "pending booking requests are never late",
+			status:      SessionStatusPending,
+			scheduledAt: now.Add(time.Hour),
+			window:      24,
+			want:        SessionStatusCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := Session{Status: tt.status, ScheduledAt: tt.scheduledAt}
+			if got := session.ClassifyCancellation(tt.window, now); got != tt.want {
+				t.Errorf("ClassifyCancellation() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBeforeSaveClearsNeedsAttendance(t *testing.T) {
+	past := time.Now().Add(-3 * time.Hour)
+	future := time.Now().Add(3 * time.Hour)
+
+	tests := []struct {
+		name        string
+		status      SessionStatus
+		scheduledAt time.Time
+		want        bool
+	}{
+		{"still unmarked", SessionStatusScheduled, past, true},
+		{"marked completed", SessionStatusCompleted, past, false},
+		{"marked no-show", SessionStatusNoShow, past, false},
+		{"moved into the future", SessionStatusScheduled, future, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := Session{Status: tt.status, ScheduledAt: tt.scheduledAt, DurationMinutes: 60, NeedsAttendance: true}
+			if err := session.BeforeSave(nil); err != nil {
+				t.Fatalf("BeforeSave() failed: %v", err)
+			}
+			if session.NeedsAttendance != tt.want {
+				t.Errorf("NeedsAttendance = %v, want %v", session.NeedsAttendance, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SkinfoldProtocol is the set of caliper sites measured
+type SkinfoldProtocol string
+
+const (
+	// Men: chest, abdominal, thigh. Women: triceps, suprailiac, thigh.
+	SkinfoldThreeSite SkinfoldProtocol = "3_site"
+	// Chest, midaxillary, triceps, subscapular, abdominal, suprailiac, thigh
+	SkinfoldSevenSite SkinfoldProtocol = "7_site"
+)
+
+// Skinfold is a caliper measurement in millimetres. Body density comes from the
+// Jackson-Pollock equations and body fat from density with the Siri equation;
+// both are calculated with the client's sex and age when the record is saved.
+type Skinfold struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID       uuid.UUID        `gorm:"type:uuid;not null;index" json:"client_id"`
+	Protocol       SkinfoldProtocol `gorm:"type:varchar(10);not null" json:"protocol"`
+	ChestMm        *float64         `json:"chest_mm,omitempty"`
+	MidaxillaryMm  *float64         `json:"midaxillary_mm,omitempty"`
+	TricepsMm      *float64         `json:"triceps_mm,omitempty"`
+	SubscapularMm  *float64         `json:"subscapular_mm,omitempty"`
+	AbdominalMm    *float64         `json:"abdominal_mm,omitempty"`
+	SuprailiacMm   *float64         `json:"suprailiac_mm,omitempty"`
+	ThighMm        *float64         `json:"thigh_mm,omitempty"`
+	Sex            Sex              `gorm:"type:varchar(10);not null" json:"sex"`
+	Age            int              `gorm:"not null" json:"age"`
+	SumMm          float64          `gorm:"type:numeric(6,1)" json:"sum_mm"`
+	BodyDensity    float64          `gorm:"type:numeric(7,5)" json:"body_density"`
+	BodyFatPercent float64          `gorm:"type:numeric(4,1)" json:"body_fat_percent"`
+	Notes          string           `gorm:"type:text" json:"notes,omitempty"`
+	MeasuredAt     time.Time        `gorm:"not null;index" json:"measured_at"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt   `gorm:"index" json:"-"`
+}
+
+// SaveSkinfoldRequest represents the request body for creating or updating a skinfold measurement.
+// Age defaults to the client's.
+type SaveSkinfoldRequest struct {
+	Protocol      SkinfoldProtocol `json:"protocol" binding:"required,oneof=3_site 7_site"`
+	ChestMm       *float64         `json:"chest_mm" binding:"omitempty,gt=0"`
+	MidaxillaryMm *float64         `json:"midaxillary_mm" binding:"omitempty,gt=0"`
+	TricepsMm     *float64         `json:"triceps_mm" binding:"omitempty,gt=0"`
+	SubscapularMm *float64         `json:"subscapular_mm" binding:"omitempty,gt=0"`
+	AbdominalMm   *float64         `json:"abdominal_mm" binding:"omitempty,gt=0"`
+	SuprailiacMm  *float64         `json:"suprailiac_mm" binding:"omitempty,gt=0"`
+	ThighMm       *float64         `json:"thigh_mm" binding:"omitempty,gt=0"`
+	Age           *int             `json:"age" binding:"omitempty,min=10,max=100"`
+	Notes         string           `json:"notes"`
+	MeasuredAt    time.Time        `json:"measured_at"`
+}
+
+// TableName overrides the table name
+func (Skinfold) TableName() string {
+	return "skinfolds"
+}
+
+// skinfoldSite is a named caliper site of a record
+type skinfoldSite struct {
+	name  string
+	value *float64
+}
+
+// sites returns the sites the protocol uses for the record's sex
+func (s *Skinfold) sites() []skinfoldSite {
+	if s.Protocol == SkinfoldSevenSite {
+		return []skinfoldSite{
+			{"chest_mm", s.ChestMm},
+			{"midaxillary_mm", s.MidaxillaryMm},
+			{"triceps_mm", s.TricepsMm},
+			{"subscapular_mm", s.SubscapularMm},
+			{"abdominal_mm", s.AbdominalMm},
+			{"suprailiac_mm", s.SuprailiacMm},
+			{"thigh_mm", s.ThighMm},
+		}
+	}
+	if s.Sex == SexFemale {
+		return []skinfoldSite{
+			{"triceps_mm", s.TricepsMm},
+			{"suprailiac_mm", s.SuprailiacMm},
+			{"thigh_mm", s.ThighMm},
+		}
+	}
+	return []skinfoldSite{
+		{"chest_mm", s.ChestMm},
+		{"abdominal_mm", s.AbdominalMm},
+		{"thigh_mm", s.ThighMm},
+	}
+}
+
+// Calculate sums the protocol's sites and calculates body density and body fat.
+// It returns a message describing the problem if the record cannot be calculated.
+func (s *Skinfold) Calculate() string {
+	if s.Sex != SexMale && s.Sex != SexFemale {
+		return "The client's sex is required to calculate body fat from skinfolds"
+	}
+	if s.Age <= 0 {
+		return "Age is required to calculate body fat from skinfolds"
+	}
+
+	sum := 0.0
+	for _, site := range s.sites() {
+		if site.value == nil {
+			return site.name + " is required for the " + string(s.Protocol) + " protocol"
+		}
+		sum += *site.value
+	}
+
+	// Jackson-Pollock body density from the sum of skinfolds and age
+	age := float64(s.Age)
+	var density float64
+	switch {
+	case s.Protocol == SkinfoldSevenSite && s.Sex == SexMale:
+		density = 1.112 - 0.00043499*sum + 0.00000055*sum*sum - 0.00028826*age
+	case s.Protocol == SkinfoldSevenSite:
+		density = 1.097 - 0.00046971*sum + 0.00000056*sum*sum - 0.00012828*age
+	case s.Sex == SexMale:
+		density = 1.10938 - 0.0008267*sum + 0.0000016*sum*sum - 0.0002574*age
+	default:
+		density = 1.0994921 - 0.0009929*sum + 0.0000023*sum*sum - 0.0001392*age
+	}
+
+	s.SumMm = math.Round(sum*10) / 10
+	s.BodyDensity = math.Round(density*100000) / 100000
+	s.BodyFatPercent = math.Max(0, math.Round((495/density-450)*10)/10)
+	return ""
+}
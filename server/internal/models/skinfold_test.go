@@ -0,0 +1,82 @@
+package models
+
+import "testing"
+
+func TestSkinfoldCalculate(t *testing.T) {
+	tests := []struct {
+		name        string
+		skinfold    Skinfold
+		wantProblem bool
+		wantSum     float64
+		wantDensity float64
+		wantBodyFat float64
+	}{
+		{
+			name: "3-site male",
+			skinfold: Skinfold{
+				Protocol: SkinfoldThreeSite, Sex: SexMale, Age: 30,
+				ChestMm: float(15), AbdominalMm: float(25), ThighMm: float(20),
+			},
+			wantSum:     60,
+			wantDensity: 1.05782,
+			wantBodyFat: 17.9,
+		},
+		{
+			name: "3-site female",
+			skinfold: Skinfold{
+				Protocol: SkinfoldThreeSite, Sex: SexFemale, Age: 25,
+				TricepsMm: float(20), SuprailiacMm: float(20), ThighMm: float(30),
+			},
+			wantSum:     70,
+			wantDensity: 1.03778,
+			wantBodyFat: 27.0,
+		},
+		{
+			name: "7-site male",
+			skinfold: Skinfold{
+				Protocol: SkinfoldSevenSite, Sex: SexMale, Age: 40,
+				ChestMm: float(15), MidaxillaryMm: float(15), TricepsMm: float(15), SubscapularMm: float(20),
+				AbdominalMm: float(25), SuprailiacMm: float(15), ThighMm: float(15),
+			},
+			wantSum:     120,
+			wantDensity: 1.05619,
+			wantBodyFat: 18.7,
+		},
+		{
+			name: "missing site",
+			skinfold: Skinfold{
+				Protocol: SkinfoldThreeSite, Sex: SexMale, Age: 30,
+				ChestMm: float(15), ThighMm: float(20),
+			},
+			wantProblem: true,
+		},
+		{
+			name: "unknown sex",
+			skinfold: Skinfold{
+				Protocol: SkinfoldThreeSite, Age: 30,
+				ChestMm: float(15), AbdominalMm: float(25), ThighMm: float(20),
+			},
+			wantProblem: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := tt.skinfold.Calculate()
+			if tt.wantProblem {
+				if problem == "" {
+					t.Error("Calculate() succeeded, want a problem")
+				}
+				return
+			}
+			if problem != "" {
+				t.Fatalf("Calculate() = %q, want no problem", problem)
+			}
+			if tt.skinfold.SumMm != tt.wantSum || tt.skinfold.BodyDensity != tt.wantDensity || tt.skinfold.BodyFatPercent != tt.wantBodyFat {
+				t.Errorf("Calculate() gave sum %v, density %v, body fat %v, want %v, %v, %v",
+					tt.skinfold.SumMm, tt.skinfold.BodyDensity, tt.skinfold.BodyFatPercent,
+					tt.wantSum, tt.wantDensity, tt.wantBodyFat)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StrengthRecord is the result of a strength test, e.g. a squat 5-rep max.
+// Estimated one-rep maxes are calculated when the record is saved.
+type StrengthRecord struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	ExerciseID     *uuid.UUID     `gorm:"type:uuid;index" json:"exercise_id,omitempty"` // library exercise, if picked from it
+	Lift           string         `gorm:"size:255;not null" json:"lift"`
+	LoadKg         float64        `gorm:"not null" json:"load_kg"` // stored unrounded, like measurements
+	Reps           int            `gorm:"not null" json:"reps"`
+	EpleyOneRM     float64        `gorm:"type:numeric(6,2)" json:"epley_one_rm"`
+	BrzyckiOneRM   float64        `gorm:"type:numeric(6,2)" json:"brzycki_one_rm"`
+	EstimatedOneRM float64        `gorm:"type:numeric(6,2)" json:"estimated_one_rm"` // average of the two formulas
+	IsPersonalBest bool           `gorm:"not null;default:false" json:"is_personal_best"`
+	TestedAt       time.Time      `gorm:"not null;index" json:"tested_at"`
+	Notes          string         `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Calculated for responses and not stored
+	UnitSystem UnitSystem           `gorm:"-" json:"unit_system"`
+	Values     map[string]UnitValue `gorm:"-" json:"values"` // load and one-rep maxes in unit_system
+}
+
+// CreateStrengthRecordRequest represents the request body for recording a strength test.
+// Lift defaults to the library exercise's name. The load is given as load_kg, or
+// as values.load in unit_system (defaults to the client's).
+type CreateStrengthRecordRequest struct {
+	ExerciseID *uuid.UUID         `json:"exercise_id"`
+	Lift       string             `json:"lift" binding:"required_without=ExerciseID"`
+	LoadKg     float64            `json:"load_kg" binding:"omitempty,gt=0"`
+	Values     map[string]float64 `json:"values"`
+	UnitSystem UnitSystem         `json:"unit_system"`
+	Reps       int                `json:"reps" binding:"required,min=1,max=30"`
+	TestedAt   time.Time          `json:"tested_at"`
+	Notes      string             `json:"notes"`
+}
+
+// UpdateStrengthRecordRequest represents the request body for updating a strength test
+type UpdateStrengthRecordRequest struct {
+	Lift       *string            `json:"lift"`
+	LoadKg     *float64           `json:"load_kg" binding:"omitempty,gt=0"`
+	Values     map[string]float64 `json:"values"` // load instead of load_kg, in unit_system
+	UnitSystem UnitSystem         `json:"unit_system"`
+	Reps       *int               `json:"reps" binding:"omitempty,min=1,max=30"`
+	TestedAt   *time.Time         `json:"tested_at"`
+	Notes      *string            `json:"notes"`
+}
+
+// StrengthPoint is one test in a lift's progression
+type StrengthPoint struct {
+	RecordID       uuid.UUID            `json:"record_id"`
+	TestedAt       time.Time            `json:"tested_at"`
+	LoadKg         float64              `json:"load_kg"`
+	Reps           int                  `json:"reps"`
+	EstimatedOneRM float64              `json:"estimated_one_rm"`
+	IsPersonalBest bool                 `json:"is_personal_best"`
+	Values         map[string]UnitValue `json:"values"` // load and estimated one-rep max in the progress' unit_system
+}
+
+// StrengthProgress is the time series of a lift for charting
+type StrengthProgress struct {
+	Lift        string               `json:"lift"`
+	BestOneRM   float64              `json:"best_one_rm"`
+	LatestOneRM float64              `json:"latest_one_rm"`
+	ChangeOneRM float64              `json:"change_one_rm"` // latest minus first estimate
+	UnitSystem  UnitSystem           `json:"unit_system"`
+	Values      map[string]UnitValue `json:"values"` // the one-rep maxes in unit_system
+	Points      []StrengthPoint      `json:"points"`
+}
+
+// TableName overrides the table name
+func (StrengthRecord) TableName() string {
+	return "strength_records"
+}
+
+// EpleyOneRM estimates a one-rep max as load × (1 + reps / 30)
+func EpleyOneRM(loadKg float64, reps int) float64 {
+	if reps <= 1 {
+		return loadKg
+	}
+	return loadKg * (1 + float64(reps)/30)
+}
+
+// BrzyckiOneRM estimates a one-rep max as load × 36 / (37 - reps)
+func BrzyckiOneRM(loadKg float64, reps int) float64 {
+	if reps <= 1 {
+		return loadKg
+	}
+	if reps >= 37 {
+		return 0
+	}
+	return loadKg * 36 / float64(37-reps)
+}
+
+// Estimate calculates the record's estimated one-rep maxes
+func (r *StrengthRecord) Estimate() {
+	r.EpleyOneRM = roundKg(EpleyOneRM(r.LoadKg, r.Reps))
+	r.BrzyckiOneRM = roundKg(BrzyckiOneRM(r.LoadKg, r.Reps))
+	r.EstimatedOneRM = roundKg((r.EpleyOneRM + r.BrzyckiOneRM) / 2)
+}
+
+// Present fills in the record's load and one-rep maxes in the unit system for a response
+func (r *StrengthRecord) Present(system UnitSystem) {
+	load, epley, brzycki, estimated := &r.LoadKg, &r.EpleyOneRM, &r.BrzyckiOneRM, &r.EstimatedOneRM
+	r.UnitSystem = system
+	r.Values = presentUnitFields(system, []unitField{
+		{key: "load", dimension: DimensionMass, value: &load},
+		{key: "epley_one_rm", dimension: DimensionMass, value: &epley},
+		{key: "brzycki_one_rm", dimension: DimensionMass, value: &brzycki},
+		{key: "estimated_one_rm", dimension: DimensionMass, value: &estimated},
+	})
+}
+
+// ApplyValues stores a load given in the unit system as values.load on the record in kg.
+// It returns a message describing the problem if a value is invalid.
+func (r *StrengthRecord) ApplyValues(system UnitSystem, values map[string]float64) string {
+	load := &r.LoadKg
+	if problem := applyUnitFields(system, values, []unitField{{key: "load", dimension: DimensionMass, value: &load}}); problem != "" {
+		return problem
+	}
+	r.LoadKg = *load
+	return ""
+}
+
+// Present fills in the progress' one-rep maxes and each point in the unit system for a response
+func (p *StrengthProgress) Present(system UnitSystem) {
+	best, latest, change := &p.BestOneRM, &p.LatestOneRM, &p.ChangeOneRM
+	p.UnitSystem = system
+	p.Values = presentUnitFields(system, []unitField{
+		{key: "best_one_rm", dimension: DimensionMass, value: &best},
+		{key: "latest_one_rm", dimension: DimensionMass, value: &latest},
+		{key: "change_one_rm", dimension: DimensionMass, value: &change},
+	})
+	for i := range p.Points {
+		load, estimated := &p.Points[i].LoadKg, &p.Points[i].EstimatedOneRM
+		p.Points[i].Values = presentUnitFields(system, []unitField{
+			{key: "load", dimension: DimensionMass, value: &load},
+			{key: "estimated_one_rm", dimension: DimensionMass, value: &estimated},
+		})
+	}
+}
+
+// roundKg rounds a load to two decimal places
+func roundKg(kg float64) float64 {
+	return math.Round(kg*100) / 100
+}
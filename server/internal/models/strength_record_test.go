@@ -0,0 +1,43 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOneRMFormulas(t *testing.T) {
+	tests := []struct {
+		name        string
+		load        float64
+		reps        int
+		wantEpley   float64
+		wantBrzycki float64
+	}{
+		{"single", 140, 1, 140, 140},
+		{"five reps", 100, 5, 116.6667, 112.5},
+		{"eight reps", 80, 8, 101.3333, 99.3103},
+		{"ten reps", 60, 10, 80, 80},
+		{"too many reps for Brzycki", 20, 37, 44.6667, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EpleyOneRM(tt.load, tt.reps); math.Abs(got-tt.wantEpley) > 1e-4 {
+				t.Errorf("EpleyOneRM(%v, %d) = %v, want %v", tt.load, tt.reps, got, tt.wantEpley)
+			}
+			if got := BrzyckiOneRM(tt.load, tt.reps); math.Abs(got-tt.wantBrzycki) > 1e-4 {
+				t.Errorf("BrzyckiOneRM(%v, %d) = %v, want %v", tt.load, tt.reps, got, tt.wantBrzycki)
+			}
+		})
+	}
+}
+
+func TestStrengthRecordEstimate(t *testing.T) {
+	record := StrengthRecord{LoadKg: 80, Reps: 8}
+	record.Estimate()
+
+	if record.EpleyOneRM != 101.33 || record.BrzyckiOneRM != 99.31 || record.EstimatedOneRM != 100.32 {
+		t.Errorf("Estimate() gave Epley %v, Brzycki %v, estimate %v, want 101.33, 99.31, 100.32",
+			record.EpleyOneRM, record.BrzyckiOneRM, record.EstimatedOneRM)
+	}
+}
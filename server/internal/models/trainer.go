@@ -10,20 +10,107 @@ import (
 
 // Trainer represents a personal trainer (PT) user
 type Trainer struct {
-	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	Email        string         `gorm:"size:255;uniqueIndex;not null" json:"email"`
-	PasswordHash string         `gorm:"size:255;not null" json:"-"`
-	FirstName    string         `gorm:"size:100;not null" json:"first_name"`
-	LastName     string         `gorm:"size:100;not null" json:"last_name"`
-	TermsAcceptedAt *time.Time    `json:"terms_accepted_at"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Email           string     `gorm:"size:255;uniqueIndex;not null" json:"email"`
+	PasswordHash    string     `gorm:"size:255;not null" json:"-"`
+	FirstName       string     `gorm:"size:100;not null" json:"first_name"`
+	LastName        string     `gorm:"size:100;not null" json:"last_name"`
+	TermsAcceptedAt *time.Time `json:"terms_accepted_at"`
+
+	// Scheduling settings
+	Timezone           string `gorm:"size:64;not null;default:'Europe/Istanbul'" json:"timezone"`
+	OutsideHoursPolicy string `gorm:"size:20;not null;default:'warn'" json:"outside_hours_policy"`
+
+	// Cancellations less than this many hours before start count as used (0 = disabled)
+	LateCancelWindowHours int `gorm:"not null;default:0" json:"late_cancel_window_hours"`
+
+	// What the no-show worker does with finished sessions that were never marked
+	NoShowPolicy string `gorm:"size:20;not null;default:'flag'" json:"no_show_policy"`
+
+	// Public self-booking settings
+	BookingToken           *string `gorm:"size:64;uniqueIndex" json:"booking_token,omitempty"`
+	BookingEnabled         bool    `gorm:"not null;default:false" json:"booking_enabled"`
+	BookingDurationMinutes int     `gorm:"not null;default:60" json:"booking_duration_minutes"`
+	BookingMinNoticeHours  int     `gorm:"not null;default:24" json:"booking_min_notice_hours"`
+	BookingMaxAdvanceDays  int     `gorm:"not null;default:30" json:"booking_max_advance_days"`
+
+	// Business details printed on invoices
+	BusinessName    string `gorm:"size:255" json:"business_name"`
+	BusinessAddress string `gorm:"type:text" json:"business_address"`
+	BusinessPhone   string `gorm:"size:20" json:"business_phone"`
+	TaxOffice       string `gorm:"size:100" json:"tax_office"`
+	TaxNumber       string `gorm:"size:20" json:"tax_number"`
+
+	// Unit system for measured values; clients can override it
+	UnitSystem UnitSystem `gorm:"type:varchar(10);not null;default:'metric'" json:"unit_system"`
+
+	// Thresholds for flagging clients as at risk in retention analytics
+	AtRiskMinAttendanceRate  float64 `gorm:"not null;default:75" json:"at_risk_min_attendance_rate"`
+	AtRiskMaxNoShowRate      float64 `gorm:"not null;default:20" json:"at_risk_max_no_show_rate"`
+	AtRiskInactiveDays       int     `gorm:"not null;default:14" json:"at_risk_inactive_days"`
+	AtRiskMinSessionsPerWeek float64 `gorm:"not null;default:1" json:"at_risk_min_sessions_per_week"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
 	Clients []Client `gorm:"foreignKey:TrainerID" json:"clients,omitempty"`
 }
 
+// Outside working hours policies
+const (
+	OutsideHoursWarn   = "warn"
+	OutsideHoursReject = "reject"
+)
+
+// No-show policies for sessions whose attendance was never recorded
+const (
+	NoShowPolicyMark = "mark_no_show"
+	NoShowPolicyFlag = "flag"
+	NoShowPolicyOff  = "off"
+)
+
+// DefaultTimezone is used when a trainer has no valid timezone set
+const DefaultTimezone = "Europe/Istanbul"
+
+// UpdateSettingsRequest represents the request body for updating trainer settings
+type UpdateSettingsRequest struct {
+	Timezone           *string `json:"timezone"`
+	OutsideHoursPolicy *string `json:"outside_hours_policy"`
+
+	LateCancelWindowHours *int    `json:"late_cancel_window_hours"`
+	NoShowPolicy          *string `json:"no_show_policy"`
+
+	BookingEnabled         *bool `json:"booking_enabled"`
+	BookingDurationMinutes *int  `json:"booking_duration_minutes"`
+	BookingMinNoticeHours  *int  `json:"booking_min_notice_hours"`
+	BookingMaxAdvanceDays  *int  `json:"booking_max_advance_days"`
+
+	BusinessName    *string `json:"business_name"`
+	BusinessAddress *string `json:"business_address"`
+	BusinessPhone   *string `json:"business_phone"`
+	TaxOffice       *string `json:"tax_office"`
+	TaxNumber       *string `json:"tax_number"`
+
+	UnitSystem *UnitSystem `json:"unit_system"`
+
+	AtRiskMinAttendanceRate  *float64 `json:"at_risk_min_attendance_rate"`
+	AtRiskMaxNoShowRate      *float64 `json:"at_risk_max_no_show_rate"`
+	AtRiskInactiveDays       *int     `json:"at_risk_inactive_days"`
+	AtRiskMinSessionsPerWeek *float64 `json:"at_risk_min_sessions_per_week"`
+}
+
+// PublicTrainerProfile is the trainer information shown on the public booking page
+type PublicTrainerProfile struct {
+	FirstName              string `json:"first_name"`
+	LastName               string `json:"last_name"`
+	Timezone               string `json:"timezone"`
+	BookingDurationMinutes int    `json:"booking_duration_minutes"`
+	BookingMinNoticeHours  int    `json:"booking_min_notice_hours"`
+	BookingMaxAdvanceDays  int    `json:"booking_max_advance_days"`
+}
+
 // RegisterRequest represents the request body for trainer registration
 type RegisterRequest struct {
 	Email         string `json:"email" binding:"required,email"`
@@ -60,6 +147,34 @@ func (t *Trainer) SetPassword(password string) error {
 	return nil
 }
 
+// Location returns the trainer's timezone, falling back to the default
+func (t *Trainer) Location() *time.Location {
+	if loc, err := time.LoadLocation(t.Timezone); err == nil && t.Timezone != "" {
+		return loc
+	}
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// BookingWindow returns the earliest and latest start times clients may book
+func (t *Trainer) BookingWindow(now time.Time) (time.Time, time.Time) {
+	return now.Add(time.Duration(t.BookingMinNoticeHours) * time.Hour),
+		now.AddDate(0, 0, t.BookingMaxAdvanceDays)
+}
+
+// RetentionThresholds returns the trainer's at-risk thresholds
+func (t *Trainer) RetentionThresholds() RetentionThresholds {
+	return RetentionThresholds{
+		MinAttendanceRate:  t.AtRiskMinAttendanceRate,
+		MaxNoShowRate:      t.AtRiskMaxNoShowRate,
+		MaxInactiveDays:    t.AtRiskInactiveDays,
+		MinSessionsPerWeek: t.AtRiskMinSessionsPerWeek,
+	}
+}
+
 // CheckPassword verifies the password against the stored hash
 func (t *Trainer) CheckPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(t.PasswordHash), []byte(password))
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBookingWindow(t *testing.T) {
+	now := time.Date(2026, time.January, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		noticeHours  int
+		advanceDays  int
+		wantEarliest time.Time
+		wantLatest   time.Time
+	}{
+		{
+			name:         "defaults",
+			noticeHours:  24,
+			advanceDays:  30,
+			wantEarliest: time.Date(2026, time.January, 6, 10, 0, 0, 0, time.UTC),
+			wantLatest:   time.Date(2026, time.February, 4, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "no notice",
+			noticeHours:  0,
+			advanceDays:  7,
+			wantEarliest: now,
+			wantLatest:   time.Date(2026, time.January, 12, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trainer := Trainer{BookingMinNoticeHours: tt.noticeHours, BookingMaxAdvanceDays: tt.advanceDays}
+			earliest, latest := trainer.BookingWindow(now)
+			if !earliest.Equal(tt.wantEarliest) || !latest.Equal(tt.wantLatest) {
+				t.Errorf("BookingWindow() = %v, %v, want %v, %v", earliest, latest, tt.wantEarliest, tt.wantLatest)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package models
+
+import "math"
+
+// Acute:chronic workload ratios outside this band are flagged
+const (
+	ACWRLow  = 0.8
+	ACWRHigh = 1.3
+)
+
+// DailyLoad is a client's session-RPE training load on one day.
+// Acute load covers the last 7 days and chronic load is the weekly average of the last 28.
+type DailyLoad struct {
+	Date        string   `json:"date"` // YYYY-MM-DD in the trainer's timezone
+	Sessions    int      `json:"sessions"`
+	Load        int      `json:"load"`
+	AcuteLoad   int      `json:"acute_load"`
+	ChronicLoad float64  `json:"chronic_load"`
+	ACWR        *float64 `json:"acwr"`
+	ACWRWarning bool     `json:"acwr_warning"`
+}
+
+// WeeklyLoad is a client's session-RPE training load for one week (Monday to Sunday).
+// The ACWR is taken on the last day of the week, or today for the current week.
+type WeeklyLoad struct {
+	WeekStart   string   `json:"week_start"` // YYYY-MM-DD
+	Sessions    int      `json:"sessions"`
+	Load        int      `json:"load"`
+	Monotony    *float64 `json:"monotony"` // mean / standard deviation of the daily loads
+	Strain      *float64 `json:"strain"`   // weekly load × monotony
+	ACWR        *float64 `json:"acwr"`
+	ACWRWarning bool     `json:"acwr_warning"`
+}
+
+// TrainingLoadResponse is returned by the training load endpoints
+type TrainingLoadResponse struct {
+	Timezone string       `json:"timezone"`
+	ACWRLow  float64      `json:"acwr_low"`
+	ACWRHigh float64      `json:"acwr_high"`
+	Days     []DailyLoad  `json:"days,omitempty"`
+	Weeks    []WeeklyLoad `json:"weeks,omitempty"`
+}
+
+// TrainingLoad returns the session load, RPE × duration in minutes,
+// or 0 if no RPE was recorded
+func (s *Session) TrainingLoad() int {
+	if s.RPE == nil {
+		return 0
+	}
+	return *s.RPE * s.DurationMinutes
+}
+
+// ACWR returns the acute:chronic workload ratio, or nil without chronic load
+func ACWR(acute int, chronic float64) *float64 {
+	if chronic <= 0 {
+		return nil
+	}
+	ratio := roundLoad(float64(acute) / chronic)
+	return &ratio
+}
+
+// IsACWRWarning reports whether a ratio is outside the 0.8-1.3 band
+func IsACWRWarning(acwr *float64) bool {
+	return acwr != nil && (*acwr < ACWRLow || *acwr > ACWRHigh)
+}
+
+// Monotony returns the mean daily load divided by its standard deviation,
+// or nil when the loads do not vary
+func Monotony(daily []int) *float64 {
+	if len(daily) == 0 {
+		return nil
+	}
+
+	var sum float64
+	for _, load := range daily {
+		sum += float64(load)
+	}
+	mean := sum / float64(len(daily))
+
+	var variance float64
+	for _, load := range daily {
+		variance += (float64(load) - mean) * (float64(load) - mean)
+	}
+	sd := math.Sqrt(variance / float64(len(daily)))
+	if sd == 0 {
+		return nil
+	}
+
+	monotony := roundLoad(mean / sd)
+	return &monotony
+}
+
+// roundLoad rounds a load statistic to two decimal places
+func roundLoad(value float64) float64 {
+	return math.Round(value*100) / 100
+}
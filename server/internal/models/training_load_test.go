@@ -0,0 +1,101 @@
+package models
+
+import "testing"
+
+func TestMonotony(t *testing.T) {
+	tests := []struct {
+		name  string
+		daily []int
+		want  *float64
+	}{
+		{
+			name: "no days",
+		},
+		{
+			name:  "loads that do not vary",
+			daily: []int{300, 300, 300, 300, 300, 300, 300},
+		},
+		{
+			name:  "steadily rising loads",
+			daily: []int{100, 200, 300, 400, 500, 600, 700},
+			want:  float(2),
+		},
+		{
+			name:  "alternating training and rest days",
+			daily: []int{300, 0, 300, 0, 300, 0, 300},
+			want:  float(1.15),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Monotony(tt.daily)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("Monotony() = %v, want nil", *got)
+			case tt.want != nil && got == nil:
+				t.Errorf("Monotony() = nil, want %v", *tt.want)
+			case tt.want != nil && *got != *tt.want:
+				t.Errorf("Monotony() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestACWR(t *testing.T) {
+	tests := []struct {
+		name        string
+		acute       int
+		chronic     float64
+		want        *float64
+		wantWarning bool
+	}{
+		{
+			name:  "no chronic load",
+			acute: 500,
+		},
+		{
+			name:    "within the band",
+			acute:   400,
+			chronic: 400,
+			want:    float(1),
+		},
+		{
+			name:        "spike",
+			acute:       600,
+			chronic:     400,
+			want:        float(1.5),
+			wantWarning: true,
+		},
+		{
+			name:        "drop",
+			acute:       500,
+			chronic:     700,
+			want:        float(0.71),
+			wantWarning: true,
+		},
+		{
+			name:    "upper edge of the band",
+			acute:   520,
+			chronic: 400,
+			want:    float(1.3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ACWR(tt.acute, tt.chronic)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("ACWR() = %v, want nil", *got)
+			case tt.want != nil && got == nil:
+				t.Errorf("ACWR() = nil, want %v", *tt.want)
+			case tt.want != nil && *got != *tt.want:
+				t.Errorf("ACWR() = %v, want %v", *got, *tt.want)
+			}
+			if warning := IsACWRWarning(got); warning != tt.wantWarning {
+				t.Errorf("IsACWRWarning() = %v, want %v", warning, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// UnitSystem is how a trainer or client enters and reads measured values.
+// Values are always stored in metric.
+type UnitSystem string
+
+const (
+	UnitSystemMetric   UnitSystem = "metric"
+	UnitSystemImperial UnitSystem = "imperial"
+)
+
+// Dimension is the kind of quantity a measured value is
+type Dimension string
+
+const (
+	DimensionMass   Dimension = "mass"
+	DimensionLength Dimension = "length"
+)
+
+// Exact conversion factors by definition of the pound and the inch
+const (
+	kgPerLb = 0.45359237
+	cmPerIn = 2.54
+)
+
+// UnitDecimals is the number of decimals values are returned with.
+// Metric values are stored unrounded, so anything entered with up to this many
+// decimals is returned unchanged in the unit system it was entered in.
+const UnitDecimals = 2
+
+// IsValidUnitSystem checks if a unit system is valid
+func IsValidUnitSystem(s UnitSystem) bool {
+	return s == UnitSystemMetric || s == UnitSystemImperial
+}
+
+// Unit returns the unit a dimension is expressed in
+func (s UnitSystem) Unit(d Dimension) string {
+	switch {
+	case d == DimensionMass && s == UnitSystemImperial:
+		return "lb"
+	case d == DimensionMass:
+		return "kg"
+	case s == UnitSystemImperial:
+		return "in"
+	default:
+		return "cm"
+	}
+}
+
+// FromMetric converts a stored metric value to the unit system, rounded to UnitDecimals
+func (s UnitSystem) FromMetric(d Dimension, value float64) float64 {
+	if s == UnitSystemImperial {
+		if d == DimensionMass {
+			value /= kgPerLb
+		} else {
+			value /= cmPerIn
+		}
+	}
+	return roundUnit(value)
+}
+
+// roundUnit rounds a value to UnitDecimals
+func roundUnit(value float64) float64 {
+	scale := math.Pow10(UnitDecimals)
+	return math.Round(value*scale) / scale
+}
+
+// ToMetric converts a value in the unit system to metric for storage, without rounding
+func (s UnitSystem) ToMetric(d Dimension, value float64) float64 {
+	if s == UnitSystemImperial {
+		if d == DimensionMass {
+			return value * kgPerLb
+		}
+		return value * cmPerIn
+	}
+	return value
+}
+
+// UnitValue is a value in the unit system of a response, with its unit
+type UnitValue struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+// unitField is a stored metric value of a record that has a unit, under the key
+// it has in the values of requests and responses
+type unitField struct {
+	key       string
+	dimension Dimension
+	value     **float64
+	allowZero bool
+}
+
+// presentUnitFields returns the fields that are set, in the unit system
+func presentUnitFields(system UnitSystem, fields []unitField) map[string]UnitValue {
+	values := make(map[string]UnitValue)
+	for _, f := range fields {
+		if *f.value != nil {
+			values[f.key] = UnitValue{
+				Value: system.FromMetric(f.dimension, **f.value),
+				Unit:  system.Unit(f.dimension),
+			}
+		}
+	}
+	return values
+}
+
+// applyUnitFields stores values given in the unit system on the fields in metric.
+// It returns a message describing the problem if a value is invalid.
+func applyUnitFields(system UnitSystem, values map[string]float64, fields []unitField) string {
+	if !IsValidUnitSystem(system) {
+		return "unit_system must be metric or imperial"
+	}
+	for _, key := range sortedValueKeys(values) {
+		value := values[key]
+		var field *unitField
+		for i := range fields {
+			if fields[i].key == key {
+				field = &fields[i]
+			}
+		}
+		if field == nil {
+			return fmt.Sprintf("Unknown value: %s", key)
+		}
+		if value < 0 || (value == 0 && !field.allowZero) {
+			return fmt.Sprintf("%s must be positive", key)
+		}
+		metric := system.ToMetric(field.dimension, value)
+		*field.value = &metric
+	}
+	return ""
+}
+
+// sortedValueKeys returns the keys of request values in ascending order, so the
+// same request always reports the same invalid value first
+func sortedValueKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MeasurementField is a value of Measurement that has a unit.
+// Key is the name used in the values of requests and responses.
+type MeasurementField struct {
+	Key       string
+	Dimension Dimension
+	field     func(m *Measurement) **float64
+}
+
+// MeasurementFields lists the measurement values with units, in display order
+var MeasurementFields = []MeasurementField{
+	{"weight", DimensionMass, func(m *Measurement) **float64 { return &m.WeightKg }},
+	{"height", DimensionLength, func(m *Measurement) **float64 { return &m.HeightCm }},
+	{"neck", DimensionLength, func(m *Measurement) **float64 { return &m.NeckCm }},
+	{"shoulder", DimensionLength, func(m *Measurement) **float64 { return &m.ShoulderCm }},
+	{"chest", DimensionLength, func(m *Measurement) **float64 { return &m.ChestCm }},
+	{"waist", DimensionLength, func(m *Measurement) **float64 { return &m.WaistCm }},
+	{"hip", DimensionLength, func(m *Measurement) **float64 { return &m.HipCm }},
+	{"right_arm", DimensionLength, func(m *Measurement) **float64 { return &m.RightArmCm }},
+	{"left_arm", DimensionLength, func(m *Measurement) **float64 { return &m.LeftArmCm }},
+	{"right_leg", DimensionLength, func(m *Measurement) **float64 { return &m.RightLegCm }},
+	{"left_leg", DimensionLength, func(m *Measurement) **float64 { return &m.LeftLegCm }},
+}
+
+// MeasurementFieldByKey returns the measurement field with the given key
+func MeasurementFieldByKey(key string) (MeasurementField, bool) {
+	for _, f := range MeasurementFields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return MeasurementField{}, false
+}
+
+// Get returns the field's stored metric value, or nil
+func (f MeasurementField) Get(m *Measurement) *float64 {
+	return *f.field(m)
+}
+
+// Set stores a metric value in the field
+func (f MeasurementField) Set(m *Measurement, value *float64) {
+	*f.field(m) = value
+}
+
+// EffectiveUnitSystem returns the client's unit system, or the trainer's if the client has none
+func (c *Client) EffectiveUnitSystem(trainer Trainer) UnitSystem {
+	if c.UnitSystem != nil && IsValidUnitSystem(*c.UnitSystem) {
+		return *c.UnitSystem
+	}
+	if IsValidUnitSystem(trainer.UnitSystem) {
+		return trainer.UnitSystem
+	}
+	return UnitSystemMetric
+}
+
+// unitFields lists the client's values that have a unit
+func (c *Client) unitFields() []unitField {
+	return []unitField{{key: "height", dimension: DimensionLength, value: &c.HeightCm}}
+}
+
+// Present fills in the client's values in the unit system for a response
+func (c *Client) Present(system UnitSystem) {
+	c.Values = presentUnitFields(system, c.unitFields())
+}
+
+// ApplyValues stores values given in the unit system on the client in metric.
+// It returns a message describing the problem if a value is invalid.
+func (c *Client) ApplyValues(system UnitSystem, values map[string]float64) string {
+	return applyUnitFields(system, values, c.unitFields())
+}
+
+// Present fills in the measurement's values in the unit system for a response
+func (m *Measurement) Present(system UnitSystem) {
+	m.UnitSystem = system
+	m.Values = make(map[string]UnitValue)
+	for _, f := range MeasurementFields {
+		if value := f.Get(m); value != nil {
+			m.Values[f.Key] = UnitValue{
+				Value: system.FromMetric(f.Dimension, *value),
+				Unit:  system.Unit(f.Dimension),
+			}
+		}
+	}
+	for _, v := range m.CustomValues {
+		// Values of deleted metrics are kept but no longer shown
+		if v.CustomMetric.ID == uuid.Nil {
+			continue
+		}
+		// Values keep the precision they were saved with
+		m.Values[v.CustomMetric.Key] = UnitValue{
+			Value: v.Value,
+			Unit:  v.CustomMetric.Unit,
+		}
+	}
+}
+
+// ApplyValues stores the request's values, given in its unit system or else the
+// default one, on the measurement in metric. Values override the _kg/_cm fields.
+// Values of the trainer's custom metrics replace the measurement's values of those
+// metrics, keeping its others, and are stored in the metric's own unit.
+// It returns a message describing the problem if a value is invalid.
+func (r *CreateMeasurementRequest) ApplyValues(m *Measurement, defaultSystem UnitSystem, metrics []CustomMetric) string {
+	system := r.UnitSystem
+	if system == "" {
+		system = defaultSystem
+	}
+	if !IsValidUnitSystem(system) {
+		return "unit_system must be metric or imperial"
+	}
+
+	for _, key := range sortedValueKeys(r.Values) {
+		value := r.Values[key]
+		f, ok := MeasurementFieldByKey(key)
+		if !ok {
+			metric, found := customMetricByKey(metrics, key)
+			if !found {
+				return fmt.Sprintf("Unknown measurement value: %s", key)
+			}
+			if problem := metric.CheckRange(value); problem != "" {
+				return problem
+			}
+			m.setCustomValue(metric, metric.Round(value))
+			continue
+		}
+		if value <= 0 {
+			return fmt.Sprintf("%s must be positive", key)
+		}
+		metric := system.ToMetric(f.Dimension, value)
+		f.Set(m, &metric)
+	}
+	return ""
+}
+
+// setCustomValue replaces the measurement's value of a custom metric, or adds it
+func (m *Measurement) setCustomValue(metric CustomMetric, value float64) {
+	for i := range m.CustomValues {
+		if m.CustomValues[i].CustomMetricID == metric.ID {
+			m.CustomValues[i].Value = value
+			m.CustomValues[i].CustomMetric = metric
+			return
+		}
+	}
+	m.CustomValues = append(m.CustomValues, CustomMetricValue{
+		CustomMetricID: metric.ID,
+		Value:          value,
+		CustomMetric:   metric,
+	})
+}
+
+// customMetricByKey returns the metric with the given key
+func customMetricByKey(metrics []CustomMetric, key string) (CustomMetric, bool) {
+	for _, m := range metrics {
+		if m.Key == key {
+			return m, true
+		}
+	}
+	return CustomMetric{}, false
+}
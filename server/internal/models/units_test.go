@@ -0,0 +1,73 @@
+package models
+
+import "testing"
+
+func TestUnitRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		system    UnitSystem
+		dimension Dimension
+		value     float64
+		metric    float64
+	}{
+		{"metric mass", UnitSystemMetric, DimensionMass, 82.35, 82.35},
+		{"metric length", UnitSystemMetric, DimensionLength, 178.5, 178.5},
+		{"imperial mass", UnitSystemImperial, DimensionMass, 225, 102.05828325},
+		{"imperial mass with decimals", UnitSystemImperial, DimensionMass, 181.63, 82.3859821631},
+		{"imperial length", UnitSystemImperial, DimensionLength, 73, 185.42},
+		{"imperial length with decimals", UnitSystemImperial, DimensionLength, 14.25, 36.195},
+		{"zero load", UnitSystemImperial, DimensionMass, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := tt.system.ToMetric(tt.dimension, tt.value)
+			if diff := metric - tt.metric; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("ToMetric(%v) = %v, want %v", tt.value, metric, tt.metric)
+			}
+			if got := tt.system.FromMetric(tt.dimension, metric); got != tt.value {
+				t.Errorf("FromMetric(ToMetric(%v)) = %v, want it unchanged", tt.value, got)
+			}
+		})
+	}
+}
+
+func TestFromMetricRounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		system    UnitSystem
+		dimension Dimension
+		metric    float64
+		want      float64
+	}{
+		{"metric", UnitSystemMetric, DimensionMass, 82.3456, 82.35},
+		{"pounds", UnitSystemImperial, DimensionMass, 100, 220.46},
+		{"inches", UnitSystemImperial, DimensionLength, 180, 70.87},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.system.FromMetric(tt.dimension, tt.metric); got != tt.want {
+				t.Errorf("FromMetric(%v) = %v, want %v", tt.metric, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyValuesReportsTheSameProblem(t *testing.T) {
+	var loadKg *float64
+	fields := []unitField{{key: "load", dimension: DimensionMass, value: &loadKg}}
+	values := map[string]float64{"load": -5, "bar": 20, "added": 10}
+
+	req := CreateMeasurementRequest{Values: map[string]float64{"weight": -80, "waist": 0, "calf": 40}}
+
+	// Map order changes between runs, the reported problem must not
+	for i := 0; i < 20; i++ {
+		if got := applyUnitFields(UnitSystemMetric, values, fields); got != "Unknown value: added" {
+			t.Fatalf("applyUnitFields() = %q, want %q", got, "Unknown value: added")
+		}
+		if got := req.ApplyValues(&Measurement{}, UnitSystemMetric, nil); got != "Unknown measurement value: calf" {
+			t.Fatalf("ApplyValues() = %q, want %q", got, "Unknown measurement value: calf")
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Thresholds beyond which a vitals reading is flagged as unsafe to train on
+const (
+	VitalsMaxSystolic         = 160 // mmHg
+	VitalsMaxDiastolic        = 100 // mmHg
+	VitalsMinSystolic         = 90  // mmHg
+	VitalsMinDiastolic        = 60  // mmHg
+	VitalsMaxRestingHeartRate = 100 // bpm
+	VitalsMinRestingHeartRate = 40  // bpm
+	VitalsMinSpO2             = 92  // %
+	VitalsMaxGlucose          = 250 // mg/dL
+	VitalsMinGlucose          = 70  // mg/dL
+)
+
+// Vitals is a client's vital signs, usually taken before a session
+type Vitals struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClientID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"client_id"`
+	SystolicMmHg     *int           `gorm:"type:smallint" json:"systolic_mmhg,omitempty"`
+	DiastolicMmHg    *int           `gorm:"type:smallint" json:"diastolic_mmhg,omitempty"`
+	RestingHeartRate *int           `gorm:"type:smallint" json:"resting_heart_rate,omitempty"` // bpm
+	SpO2Percent      *int           `gorm:"type:smallint" json:"spo2_percent,omitempty"`
+	GlucoseMgDl      *float64       `json:"glucose_mg_dl,omitempty"`
+	Notes            string         `gorm:"type:text" json:"notes,omitempty"`
+	MeasuredAt       time.Time      `gorm:"not null;index" json:"measured_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Calculated for responses and not stored
+	Flags []string `gorm:"-" json:"flags"` // readings beyond the safety thresholds
+}
+
+// SaveVitalsRequest represents the request body for creating or updating a vitals record
+type SaveVitalsRequest struct {
+	SystolicMmHg     *int      `json:"systolic_mmhg" binding:"omitempty,min=50,max=300"`
+	DiastolicMmHg    *int      `json:"diastolic_mmhg" binding:"omitempty,min=30,max=200"`
+	RestingHeartRate *int      `json:"resting_heart_rate" binding:"omitempty,min=20,max=250"`
+	SpO2Percent      *int      `json:"spo2_percent" binding:"omitempty,min=50,max=100"`
+	GlucoseMgDl      *float64  `json:"glucose_mg_dl" binding:"omitempty,gt=0,max=1000"`
+	Notes            string    `json:"notes"`
+	MeasuredAt       time.Time `json:"measured_at"`
+}
+
+// SafetyCheckResponse is the vitals of a client on the day of a session and
+// the warnings they raise
+type SafetyCheckResponse struct {
+	SessionID uuid.UUID `json:"session_id"`
+	Date      string    `json:"date"` // YYYY-MM-DD in the trainer's timezone
+	Safe      bool      `json:"safe"` // true if there are no warnings
+	Vitals    []Vitals  `json:"vitals"`
+	Warnings  []string  `json:"warnings"`
+}
+
+// TableName overrides the table name
+func (Vitals) TableName() string {
+	return "vitals"
+}
+
+// Validate checks that the record has a reading and a consistent blood
+// pressure, and returns a description of the first problem found
+func (v *Vitals) Validate() string {
+	if v.SystolicMmHg == nil && v.DiastolicMmHg == nil && v.RestingHeartRate == nil &&
+		v.SpO2Percent == nil && v.GlucoseMgDl == nil {
+		return "At least one reading is required"
+	}
+	if (v.SystolicMmHg == nil) != (v.DiastolicMmHg == nil) {
+		return "Blood pressure needs both systolic_mmhg and diastolic_mmhg"
+	}
+	if v.SystolicMmHg != nil && *v.SystolicMmHg <= *v.DiastolicMmHg {
+		return "systolic_mmhg must be higher than diastolic_mmhg"
+	}
+	return ""
+}
+
+// SetFlags fills in a description of each reading beyond the safety thresholds
+func (v *Vitals) SetFlags() {
+	v.Flags = []string{}
+	if v.SystolicMmHg != nil && v.DiastolicMmHg != nil {
+		bp := fmt.Sprintf("Blood pressure %d/%d mmHg", *v.SystolicMmHg, *v.DiastolicMmHg)
+		switch {
+		case *v.SystolicMmHg > VitalsMaxSystolic || *v.DiastolicMmHg > VitalsMaxDiastolic:
+			v.Flags = append(v.Flags, fmt.Sprintf("%s is above %d/%d", bp, VitalsMaxSystolic, VitalsMaxDiastolic))
+		case *v.SystolicMmHg < VitalsMinSystolic || *v.DiastolicMmHg < VitalsMinDiastolic:
+			v.Flags = append(v.Flags, fmt.Sprintf("%s is below %d/%d", bp, VitalsMinSystolic, VitalsMinDiastolic))
+		}
+	}
+	if hr := v.RestingHeartRate; hr != nil {
+		switch {
+		case *hr > VitalsMaxRestingHeartRate:
+			v.Flags = append(v.Flags, fmt.Sprintf("Resting heart rate %d bpm is above %d", *hr, VitalsMaxRestingHeartRate))
+		case *hr < VitalsMinRestingHeartRate:
+			v.Flags = append(v.Flags, fmt.Sprintf("Resting heart rate %d bpm is below %d", *hr, VitalsMinRestingHeartRate))
+		}
+	}
+	if v.SpO2Percent != nil && *v.SpO2Percent < VitalsMinSpO2 {
+		v.Flags = append(v.Flags, fmt.Sprintf("SpO2 %d%% is below %d%%", *v.SpO2Percent, VitalsMinSpO2))
+	}
+	if glucose := v.GlucoseMgDl; glucose != nil {
+		switch {
+		case *glucose > VitalsMaxGlucose:
+			v.Flags = append(v.Flags, fmt.Sprintf("Blood glucose %g mg/dL is above %d", *glucose, VitalsMaxGlucose))
+		case *glucose < VitalsMinGlucose:
+			v.Flags = append(v.Flags, fmt.Sprintf("Blood glucose %g mg/dL is below %d", *glucose, VitalsMinGlucose))
+		}
+	}
+}
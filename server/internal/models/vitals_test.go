@@ -0,0 +1,108 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVitalsValidate(t *testing.T) {
+	n := func(v int) *int { return &v }
+	glucose := 95.0
+
+	tests := []struct {
+		name   string
+		vitals Vitals
+		wantOK bool
+	}{
+		{"blood pressure", Vitals{SystolicMmHg: n(120), DiastolicMmHg: n(80)}, true},
+		{"heart rate only", Vitals{RestingHeartRate: n(60)}, true},
+		{"glucose only", Vitals{GlucoseMgDl: &glucose}, true},
+		{"no reading", Vitals{}, false},
+		{"systolic without diastolic", Vitals{SystolicMmHg: n(120)}, false},
+		{"diastolic without systolic", Vitals{DiastolicMmHg: n(80)}, false},
+		{"systolic not above diastolic", Vitals{SystolicMmHg: n(80), DiastolicMmHg: n(80)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := tt.vitals.Validate()
+			if tt.wantOK && problem != "" {
+				t.Errorf("Validate() = %q, want no problem", problem)
+			}
+			if !tt.wantOK && problem == "" {
+				t.Error("Validate() found no problem")
+			}
+		})
+	}
+}
+
+func TestVitalsSetFlags(t *testing.T) {
+	n := func(v int) *int { return &v }
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name   string
+		vitals Vitals
+		want   []string
+	}{
+		{
+			name:   "normal",
+			vitals: Vitals{SystolicMmHg: n(120), DiastolicMmHg: n(80), RestingHeartRate: n(62), SpO2Percent: n(98), GlucoseMgDl: f(95)},
+			want:   []string{},
+		},
+		{
+			name:   "at the blood pressure limit",
+			vitals: Vitals{SystolicMmHg: n(160), DiastolicMmHg: n(100)},
+			want:   []string{},
+		},
+		{
+			name:   "systolic above the limit",
+			vitals: Vitals{SystolicMmHg: n(161), DiastolicMmHg: n(90)},
+			want:   []string{"Blood pressure 161/90 mmHg is above 160/100"},
+		},
+		{
+			name:   "diastolic above the limit",
+			vitals: Vitals{SystolicMmHg: n(150), DiastolicMmHg: n(101)},
+			want:   []string{"Blood pressure 150/101 mmHg is above 160/100"},
+		},
+		{
+			name:   "low blood pressure",
+			vitals: Vitals{SystolicMmHg: n(85), DiastolicMmHg: n(55)},
+			want:   []string{"Blood pressure 85/55 mmHg is below 90/60"},
+		},
+		{
+			name:   "heart rate limits",
+			vitals: Vitals{RestingHeartRate: n(100)},
+			want:   []string{},
+		},
+		{
+			name:   "fast heart rate",
+			vitals: Vitals{RestingHeartRate: n(101)},
+			want:   []string{"Resting heart rate 101 bpm is above 100"},
+		},
+		{
+			name:   "slow heart rate",
+			vitals: Vitals{RestingHeartRate: n(39)},
+			want:   []string{"Resting heart rate 39 bpm is below 40"},
+		},
+		{
+			name:   "low oxygen and high glucose",
+			vitals: Vitals{SpO2Percent: n(91), GlucoseMgDl: f(251.5)},
+			want:   []string{"SpO2 91% is below 92%", "Blood glucose 251.5 mg/dL is above 250"},
+		},
+		{
+			name:   "low glucose",
+			vitals: Vitals{GlucoseMgDl: f(69)},
+			want:   []string{"Blood glucose 69 mg/dL is below 70"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.vitals.SetFlags()
+			if !reflect.DeepEqual(tt.vitals.Flags, tt.want) {
+				t.Errorf("Flags = %q, want %q", tt.vitals.Flags, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkoutLog records what a client actually did in a session
+type WorkoutLog struct {
+	ID        uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	SessionID uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex" json:"session_id"`
+	Notes     string            `gorm:"type:text" json:"notes,omitempty"`
+	Exercises []WorkoutExercise `gorm:"foreignKey:WorkoutLogID;constraint:OnDelete:CASCADE" json:"exercises"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+
+	// Calculated for responses and not stored
+	UnitSystem UnitSystem `gorm:"-" json:"unit_system"` // of the sets' values
+}
+
+// WorkoutExercise is an exercise performed in a workout, in order
+type WorkoutExercise struct {
+	ID           uuid.UUID    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	WorkoutLogID uuid.UUID    `gorm:"type:uuid;not null;index" json:"-"`
+	ExerciseID   *uuid.UUID   `gorm:"type:uuid;index" json:"exercise_id,omitempty"` // library exercise, if picked from it
+	Position     int          `gorm:"not null" json:"position"`
+	Name         string       `gorm:"size:255;not null" json:"name"`
+	Notes        string       `gorm:"type:text" json:"notes,omitempty"`
+	Sets         []WorkoutSet `gorm:"foreignKey:WorkoutExerciseID;constraint:OnDelete:CASCADE" json:"sets"`
+}
+
+// WorkoutSet is a single set of an exercise
+type WorkoutSet struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	WorkoutExerciseID uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	Position          int       `gorm:"not null" json:"position"`
+	Reps              *int      `json:"reps,omitempty"`
+	LoadKg            *float64  `json:"load_kg,omitempty"`              // stored unrounded, like measurements
+	Tempo             string    `gorm:"size:20" json:"tempo,omitempty"` // e.g. "3-1-1-0"
+	RestSeconds       *int      `json:"rest_seconds,omitempty"`
+	RPE               *float64  `gorm:"type:numeric(3,1)" json:"rpe,omitempty"` // rate of perceived exertion, 1-10
+	RIR               *int      `json:"rir,omitempty"`                          // reps in reserve
+
+	// Calculated for responses and not stored
+	Values map[string]UnitValue `gorm:"-" json:"values,omitempty"` // load in the log's unit_system
+}
+
+// SaveWorkoutLogRequest represents the request body for creating or replacing a session's workout log.
+// Set values are in unit_system (defaults to the client's).
+type SaveWorkoutLogRequest struct {
+	Notes      string                 `json:"notes"`
+	UnitSystem UnitSystem             `json:"unit_system"`
+	Exercises  []WorkoutExerciseInput `json:"exercises" binding:"dive"`
+}
+
+// WorkoutExerciseInput is an exercise in a SaveWorkoutLogRequest
+// Name defaults to the library exercise's name.
+type WorkoutExerciseInput struct {
+	ExerciseID *uuid.UUID        `json:"exercise_id"`
+	Name       string            `json:"name" binding:"required_without=ExerciseID"`
+	Notes      string            `json:"notes"`
+	Sets       []WorkoutSetInput `json:"sets" binding:"dive"`
+}
+
+// WorkoutSetInput is a set in a SaveWorkoutLogRequest
+type WorkoutSetInput struct {
+	Reps        *int               `json:"reps" binding:"omitempty,min=0"`
+	LoadKg      *float64           `json:"load_kg" binding:"omitempty,min=0"`
+	Values      map[string]float64 `json:"values"` // load instead of load_kg
+	Tempo       string             `json:"tempo" binding:"max=20"`
+	RestSeconds *int               `json:"rest_seconds" binding:"omitempty,min=0"`
+	RPE         *float64           `json:"rpe" binding:"omitempty,min=1,max=10"`
+	RIR         *int               `json:"rir" binding:"omitempty,min=0,max=10"`
+}
+
+// TableName overrides the table name
+func (WorkoutLog) TableName() string {
+	return "workout_logs"
+}
+
+// TableName overrides the table name
+func (WorkoutExercise) TableName() string {
+	return "workout_exercises"
+}
+
+// TableName overrides the table name
+func (WorkoutSet) TableName() string {
+	return "workout_sets"
+}
+
+// WorkoutExercises converts the request into exercises numbered in the order given,
+// reading set values in the unit system. It returns a message describing the
+// problem if a value is invalid.
+func (r SaveWorkoutLogRequest) WorkoutExercises(system UnitSystem) ([]WorkoutExercise, string) {
+	exercises := make([]WorkoutExercise, 0, len(r.Exercises))
+	for i, input := range r.Exercises {
+		exercise := WorkoutExercise{
+			ExerciseID: input.ExerciseID,
+			Position:   i + 1,
+			Name:       input.Name,
+			Notes:      input.Notes,
+			Sets:       make([]WorkoutSet, 0, len(input.Sets)),
+		}
+		for j, set := range input.Sets {
+			workoutSet := WorkoutSet{
+				Position:    j + 1,
+				Reps:        set.Reps,
+				LoadKg:      set.LoadKg,
+				Tempo:       set.Tempo,
+				RestSeconds: set.RestSeconds,
+				RPE:         set.RPE,
+				RIR:         set.RIR,
+			}
+			if problem := applyUnitFields(system, set.Values, workoutSet.unitFields()); problem != "" {
+				return nil, problem
+			}
+			exercise.Sets = append(exercise.Sets, workoutSet)
+		}
+		exercises = append(exercises, exercise)
+	}
+	return exercises, ""
+}
+
+// unitFields lists the set's values that have a unit. A load of 0 is a bodyweight set.
+func (s *WorkoutSet) unitFields() []unitField {
+	return []unitField{{key: "load", dimension: DimensionMass, value: &s.LoadKg, allowZero: true}}
+}
+
+// Present fills in the loads of the log's sets in the unit system for a response
+func (l *WorkoutLog) Present(system UnitSystem) {
+	l.UnitSystem = system
+	for i := range l.Exercises {
+		for j := range l.Exercises[i].Sets {
+			set := &l.Exercises[i].Sets[j]
+			set.Values = presentUnitFields(system, set.unitFields())
+		}
+	}
+}
+
+// CanLogWorkout returns true if a workout can be logged for a session with this status:
+// planned ahead for a scheduled session, or recorded once it is completed
+func CanLogWorkout(status SessionStatus) bool {
+	return status == SessionStatusScheduled || status == SessionStatusCompleted
+}
@@ -0,0 +1,60 @@
+package models
+
+import "testing"
+
+func TestWorkoutExercises(t *testing.T) {
+	reps := func(n int) *int { return &n }
+	req := SaveWorkoutLogRequest{
+		Exercises: []WorkoutExerciseInput{
+			{Name: "Back Squat", Sets: []WorkoutSetInput{
+				{Reps: reps(5), Values: map[string]float64{"load": 225}},
+				{Reps: reps(5), Values: map[string]float64{"load": 0}},
+			}},
+			{Name: "Plank"},
+		},
+	}
+
+	exercises, problem := req.WorkoutExercises(UnitSystemImperial)
+	if problem != "" {
+		t.Fatalf("WorkoutExercises() = %q, want no problem", problem)
+	}
+	if len(exercises) != 2 || exercises[0].Position != 1 || exercises[1].Position != 2 {
+		t.Fatalf("exercises are not numbered in order: %+v", exercises)
+	}
+	sets := exercises[0].Sets
+	if len(sets) != 2 || sets[0].Position != 1 || sets[1].Position != 2 {
+		t.Fatalf("sets are not numbered in order: %+v", sets)
+	}
+	if sets[0].LoadKg == nil || *sets[0].LoadKg != 225*kgPerLb {
+		t.Errorf("first set load = %v kg, want %v", sets[0].LoadKg, 225*kgPerLb)
+	}
+	if sets[1].LoadKg == nil || *sets[1].LoadKg != 0 {
+		t.Errorf("bodyweight set load = %v kg, want 0", sets[1].LoadKg)
+	}
+
+	req.Exercises[0].Sets[0].Values = map[string]float64{"load": -5}
+	if _, problem := req.WorkoutExercises(UnitSystemMetric); problem == "" {
+		t.Error("WorkoutExercises() accepted a negative load")
+	}
+}
+
+func TestCanLogWorkout(t *testing.T) {
+	tests := []struct {
+		status SessionStatus
+		want   bool
+	}{
+		{SessionStatusScheduled, true},
+		{SessionStatusCompleted, true},
+		{SessionStatusPending, false},
+		{SessionStatusNoShow, false},
+		{SessionStatusCancelled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := CanLogWorkout(tt.status); got != tt.want {
+				t.Errorf("CanLogWorkout(%s) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"ptmate/internal/models"
+)
+
+// ParseClock parses an "HH:MM" wall-clock time
+func ParseClock(value string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// ClockRange builds a slot on the given day from "HH:MM" start and end times
+func ClockRange(day time.Time, start, end string) (models.TimeSlot, error) {
+	sh, sm, err := ParseClock(start)
+	if err != nil {
+		return models.TimeSlot{}, err
+	}
+	eh, em, err := ParseClock(end)
+	if err != nil {
+		return models.TimeSlot{}, err
+	}
+
+	y, m, d := day.Date()
+	slot := models.TimeSlot{
+		Start: time.Date(y, m, d, sh, sm, 0, 0, day.Location()),
+		End:   time.Date(y, m, d, eh, em, 0, 0, day.Location()),
+	}
+	if !slot.End.After(slot.Start) {
+		return models.TimeSlot{}, fmt.Errorf("end time %s must be after start time %s", end, start)
+	}
+	return slot, nil
+}
+
+// MergeSlots sorts slots and joins the ones that touch or overlap
+func MergeSlots(slots []models.TimeSlot) []models.TimeSlot {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	sorted := append([]models.TimeSlot(nil), slots...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	merged := []models.TimeSlot{sorted[0]}
+	for _, s := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if !s.Start.After(last.End) {
+			if s.End.After(last.End) {
+				last.End = s.End
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// SubtractSlots removes the busy periods from the free windows
+func SubtractSlots(windows, busy []models.TimeSlot) []models.TimeSlot {
+	busy = MergeSlots(busy)
+
+	var result []models.TimeSlot
+	for _, w := range MergeSlots(windows) {
+		current := w
+		for _, b := range busy {
+			if !b.End.After(current.Start) || !b.Start.Before(current.End) {
+				continue
+			}
+			if b.Start.After(current.Start) {
+				result = append(result, models.TimeSlot{Start: current.Start, End: b.Start})
+			}
+			current.Start = b.End
+			if !current.End.After(current.Start) {
+				break
+			}
+		}
+		if current.End.After(current.Start) {
+			result = append(result, current)
+		}
+	}
+	return result
+}
+
+// SplitSlots cuts free windows into slots of the given duration, starting
+// every step within each window
+func SplitSlots(windows []models.TimeSlot, duration, step time.Duration) []models.TimeSlot {
+	if duration <= 0 {
+		return nil
+	}
+	if step <= 0 {
+		step = duration
+	}
+
+	slots := make([]models.TimeSlot, 0)
+	for _, w := range windows {
+		for start := w.Start; !start.Add(duration).After(w.End); start = start.Add(step) {
+			slots = append(slots, models.TimeSlot{Start: start, End: start.Add(duration)})
+		}
+	}
+	return slots
+}
+
+// CoversSlot reports whether the target lies entirely inside one of the windows
+func CoversSlot(windows []models.TimeSlot, target models.TimeSlot) bool {
+	for _, w := range MergeSlots(windows) {
+		if !target.Start.Before(w.Start) && !target.End.After(w.End) {
+			return true
+		}
+	}
+	return false
+}
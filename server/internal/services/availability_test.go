@@ -0,0 +1,171 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"ptmate/internal/models"
+)
+
+func TestSubtractSlots(t *testing.T) {
+	day := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	slot := func(startHour, startMin, endHour, endMin int) models.TimeSlot {
+		return models.TimeSlot{
+			Start: day.Add(time.Duration(startHour)*time.Hour + time.Duration(startMin)*time.Minute),
+			End:   day.Add(time.Duration(endHour)*time.Hour + time.Duration(endMin)*time.Minute),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		windows []models.TimeSlot
+		busy    []models.TimeSlot
+		want    []models.TimeSlot
+	}{
+		{
+			name:    "nothing busy",
+			windows: []models.TimeSlot{slot(9, 0, 12, 0)},
+			want:    []models.TimeSlot{slot(9, 0, 12, 0)},
+		},
+		{
+			name:    "busy in the middle splits the window",
+			windows: []models.TimeSlot{slot(9, 0, 12, 0)},
+			busy:    []models.TimeSlot{slot(10, 0, 11, 0)},
+			want:    []models.TimeSlot{slot(9, 0, 10, 0), slot(11, 0, 12, 0)},
+		},
+		{
+			name:    "busy at the start",
+			windows: []models.TimeSlot{slot(9, 0, 12, 0)},
+			busy:    []models.TimeSlot{slot(8, 0, 9, 30)},
+			want:    []models.TimeSlot{slot(9, 30, 12, 0)},
+		},
+		{
+			name:    "busy at the end",
+			windows: []models.TimeSlot{slot(9, 0, 12, 0)},
+			busy:    []models.TimeSlot{slot(11, 30, 13, 0)},
+			want:    []models.TimeSlot{slot(9, 0, 11, 30)},
+		},
+		{
+			name:    "busy covers the window",
+			windows: []models.TimeSlot{slot(9, 0, 12, 0)},
+			busy:    []models.TimeSlot{slot(8, 0, 13, 0)},
+			want:    nil,
+		},
+		{
+			name:    "touching busy periods leave nothing between them",
+			windows: []models.TimeSlot{slot(9, 0, 12, 0)},
+			busy:    []models.TimeSlot{slot(10, 0, 11, 0), slot(9, 0, 10, 0)},
+			want:    []models.TimeSlot{slot(11, 0, 12, 0)},
+		},
+		{
+			name:    "busy outside the window",
+			windows: []models.TimeSlot{slot(9, 0, 12, 0)},
+			busy:    []models.TimeSlot{slot(12, 0, 13, 0), slot(7, 0, 9, 0)},
+			want:    []models.TimeSlot{slot(9, 0, 12, 0)},
+		},
+		{
+			name:    "overlapping windows are merged first",
+			windows: []models.TimeSlot{slot(13, 0, 15, 0), slot(9, 0, 12, 0), slot(11, 0, 14, 0)},
+			busy:    []models.TimeSlot{slot(12, 0, 12, 30)},
+			want:    []models.TimeSlot{slot(9, 0, 12, 0), slot(12, 30, 15, 0)},
+		},
+		{
+			name:    "one busy period spans two windows",
+			windows: []models.TimeSlot{slot(9, 0, 11, 0), slot(12, 0, 14, 0)},
+			busy:    []models.TimeSlot{slot(10, 0, 13, 0)},
+			want:    []models.TimeSlot{slot(9, 0, 10, 0), slot(13, 0, 14, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SubtractSlots(tt.windows, tt.busy)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Errorf("slot %d = %v-%v, want %v-%v", i,
+						got[i].Start.Format("15:04"), got[i].End.Format("15:04"),
+						tt.want[i].Start.Format("15:04"), tt.want[i].End.Format("15:04"))
+				}
+			}
+		})
+	}
+}
+
+func TestSplitSlots(t *testing.T) {
+	day := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	at := func(hour, min int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+	}
+	windows := []models.TimeSlot{{Start: at(9, 0), End: at(11, 0)}, {Start: at(14, 0), End: at(15, 30)}}
+
+	tests := []struct {
+		name     string
+		duration time.Duration
+		step     time.Duration
+		want     []time.Time
+	}{
+		{"step defaults to the duration", time.Hour, 0, []time.Time{at(9, 0), at(10, 0), at(14, 0)}},
+		{"half-hour step", time.Hour, 30 * time.Minute, []time.Time{at(9, 0), at(9, 30), at(10, 0), at(14, 0), at(14, 30)}},
+		{"longer than every window", 3 * time.Hour, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := SplitSlots(windows, tt.duration, tt.step)
+			if len(slots) != len(tt.want) {
+				t.Fatalf("got %d slots, want %d", len(slots), len(tt.want))
+			}
+			for i, s := range slots {
+				if !s.Start.Equal(tt.want[i]) || !s.End.Equal(tt.want[i].Add(tt.duration)) {
+					t.Errorf("slot %d = %v-%v, want it to start at %v", i, s.Start, s.End, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCoversSlot(t *testing.T) {
+	day := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	windows := []models.TimeSlot{{Start: at(9), End: at(12)}, {Start: at(12), End: at(14)}, {Start: at(16), End: at(18)}}
+
+	tests := []struct {
+		name   string
+		target models.TimeSlot
+		want   bool
+	}{
+		{"inside a window", models.TimeSlot{Start: at(9), End: at(10)}, true},
+		{"across touching windows", models.TimeSlot{Start: at(11), End: at(13)}, true},
+		{"across a gap", models.TimeSlot{Start: at(13), End: at(17)}, false},
+		{"outside every window", models.TimeSlot{Start: at(19), End: at(20)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CoversSlot(windows, tt.target); got != tt.want {
+				t.Errorf("CoversSlot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClockRange(t *testing.T) {
+	day := time.Date(2026, time.January, 5, 15, 0, 0, 0, time.UTC)
+
+	slot, err := ClockRange(day, "09:30", "17:00")
+	if err != nil {
+		t.Fatalf("ClockRange() failed: %v", err)
+	}
+	if !slot.Start.Equal(time.Date(2026, time.January, 5, 9, 30, 0, 0, time.UTC)) || !slot.End.Equal(time.Date(2026, time.January, 5, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("ClockRange() = %v-%v", slot.Start, slot.End)
+	}
+
+	for _, bad := range [][2]string{{"9am", "17:00"}, {"09:00", "25:00"}, {"17:00", "09:00"}, {"09:00", "09:00"}} {
+		if _, err := ClockRange(day, bad[0], bad[1]); err == nil {
+			t.Errorf("ClockRange(%s, %s) succeeded, want an error", bad[0], bad[1])
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// balanceCharge is what a client was charged in a currency, and how much of it is due
+type balanceCharge struct {
+	ClientID uuid.UUID
+	Currency string
+	Charged  float64
+	Due      float64
+}
+
+// balancePayment is what a client paid and was refunded in a currency
+type balancePayment struct {
+	ClientID uuid.UUID
+	Currency string
+	Paid     float64
+	Refunded float64
+}
+
+// ClientBalances computes the balance of each client in every currency they were
+// charged or paid in. Package prices are charged on purchase, or per installment
+// when the package has a payment plan; cancelled packages are not charged.
+func ClientBalances(db *gorm.DB, clientIDs []uuid.UUID, now time.Time) (map[uuid.UUID][]models.CurrencyBalance, error) {
+	if len(clientIDs) == 0 {
+		return map[uuid.UUID][]models.CurrencyBalance{}, nil
+	}
+
+	var charges []balanceCharge
+	if err := db.Model(&models.Package{}).
+		Select(`client_id, currency,
+			SUM(price) as charged,
+			SUM(CASE
+				WHEN EXISTS (SELECT 1 FROM package_installments i WHERE i.package_id = packages.id)
+					THEN (SELECT COALESCE(SUM(i.amount), 0) FROM package_installments i WHERE i.package_id = packages.id AND i.due_at <= ?)
+				WHEN purchased_at <= ? THEN price
+				ELSE 0
+			END) as due`, now, now).
+		Where("client_id IN ? AND status <> ?", clientIDs, models.PackageStatusCancelled).
+		Group("client_id, currency").
+		Scan(&charges).Error; err != nil {
+		return nil, err
+	}
+
+	var payments []balancePayment
+	if err := db.Model(&models.Payment{}).
+		Select(`client_id, currency,
+			SUM(CASE WHEN type = ? THEN amount ELSE 0 END) as paid,
+			SUM(CASE WHEN type = ? THEN amount ELSE 0 END) as refunded`,
+			models.PaymentTypePayment, models.PaymentTypeRefund).
+		Where("client_id IN ?", clientIDs).
+		Group("client_id, currency").
+		Scan(&payments).Error; err != nil {
+		return nil, err
+	}
+
+	return sumBalances(charges, payments), nil
+}
+
+// sumBalances combines charges and payments into each client's balances,
+// ordered by currency. Net payments reduce what is due before it counts as overdue.
+func sumBalances(charges []balanceCharge, payments []balancePayment) map[uuid.UUID][]models.CurrencyBalance {
+	type key struct {
+		ClientID uuid.UUID
+		Currency string
+	}
+	accounts := map[key]*models.CurrencyBalance{}
+	account := func(clientID uuid.UUID, currency string) *models.CurrencyBalance {
+		k := key{clientID, currency}
+		if accounts[k] == nil {
+			accounts[k] = &models.CurrencyBalance{Currency: currency}
+		}
+		return accounts[k]
+	}
+
+	for _, ch := range charges {
+		a := account(ch.ClientID, ch.Currency)
+		a.Charged = ch.Charged
+		a.Overdue = ch.Due // reduced by net payments below
+	}
+	for _, p := range payments {
+		a := account(p.ClientID, p.Currency)
+		a.Paid = p.Paid
+		a.Refunded = p.Refunded
+	}
+
+	balances := make(map[uuid.UUID][]models.CurrencyBalance)
+	for k, a := range accounts {
+		net := a.Paid - a.Refunded
+		a.Outstanding = RoundMoney(a.Charged - net)
+		a.Overdue = RoundMoney(math.Max(0, a.Overdue-net))
+		a.Charged = RoundMoney(a.Charged)
+		a.Paid = RoundMoney(a.Paid)
+		a.Refunded = RoundMoney(a.Refunded)
+		balances[k.ClientID] = append(balances[k.ClientID], *a)
+	}
+	for clientID := range balances {
+		sort.Slice(balances[clientID], func(i, j int) bool {
+			return balances[clientID][i].Currency < balances[clientID][j].Currency
+		})
+	}
+
+	return balances
+}
+
+// RoundMoney rounds an amount to cents
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"ptmate/internal/models"
+)
+
+func TestSumBalances(t *testing.T) {
+	client := uuid.New()
+
+	tests := []struct {
+		name     string
+		charges  []balanceCharge
+		payments []balancePayment
+		want     []models.CurrencyBalance
+	}{
+		{
+			name:    "nothing paid yet",
+			charges: []balanceCharge{{ClientID: client, Currency: "EUR", Charged: 300, Due: 100}},
+			want:    []models.CurrencyBalance{{Currency: "EUR", Charged: 300, Outstanding: 300, Overdue: 100}},
+		},
+		{
+			name:     "payments cover what is due",
+			charges:  []balanceCharge{{ClientID: client, Currency: "EUR", Charged: 300, Due: 100}},
+			payments: []balancePayment{{ClientID: client, Currency: "EUR", Paid: 150}},
+			want:     []models.CurrencyBalance{{Currency: "EUR", Charged: 300, Paid: 150, Outstanding: 150}},
+		},
+		{
+			name:     "refunds count against payments",
+			charges:  []balanceCharge{{ClientID: client, Currency: "EUR", Charged: 300, Due: 300}},
+			payments: []balancePayment{{ClientID: client, Currency: "EUR", Paid: 300, Refunded: 50}},
+			want:     []models.CurrencyBalance{{Currency: "EUR", Charged: 300, Paid: 300, Refunded: 50, Outstanding: 50, Overdue: 50}},
+		},
+		{
+			name:     "payments only leave the client in credit",
+			payments: []balancePayment{{ClientID: client, Currency: "USD", Paid: 80}},
+			want:     []models.CurrencyBalance{{Currency: "USD", Paid: 80, Outstanding: -80}},
+		},
+		{
+			name:     "amounts are rounded to cents",
+			charges:  []balanceCharge{{ClientID: client, Currency: "EUR", Charged: 0.1 + 0.2, Due: 0.1 + 0.2}},
+			payments: []balancePayment{{ClientID: client, Currency: "EUR", Paid: 0.1}},
+			want:     []models.CurrencyBalance{{Currency: "EUR", Charged: 0.3, Paid: 0.1, Outstanding: 0.2, Overdue: 0.2}},
+		},
+		{
+			name: "currencies are sorted",
+			charges: []balanceCharge{
+				{ClientID: client, Currency: "USD", Charged: 50},
+				{ClientID: client, Currency: "EUR", Charged: 70},
+			},
+			want: []models.CurrencyBalance{
+				{Currency: "EUR", Charged: 70, Outstanding: 70},
+				{Currency: "USD", Charged: 50, Outstanding: 50},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sumBalances(tt.charges, tt.payments)[client]
+			if len(got) != len(tt.want) {
+				t.Fatalf("sumBalances() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("sumBalances()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSumBalancesSeparatesClients(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	got := sumBalances(
+		[]balanceCharge{{ClientID: alice, Currency: "EUR", Charged: 100}},
+		[]balancePayment{{ClientID: bob, Currency: "EUR", Paid: 40}},
+	)
+
+	if len(got[alice]) != 1 || got[alice][0].Outstanding != 100 {
+		t.Errorf("alice = %+v, want 100 outstanding", got[alice])
+	}
+	if len(got[bob]) != 1 || got[bob][0].Outstanding != -40 {
+		t.Errorf("bob = %+v, want 40 in credit", got[bob])
+	}
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// trendPoint is a goal value at a number of days after the goal started
+type trendPoint struct {
+	Days  float64
+	Value float64
+}
+
+// RefreshGoals evaluates the client's active and achieved goals, so that a new
+// measurement or session can achieve them and a corrected or deleted one can undo it
+func RefreshGoals(tx *gorm.DB, clientID uuid.UUID, loc *time.Location, now time.Time) error {
+	var goals []models.Goal
+	if err := tx.Where("client_id = ? AND status IN ?", clientID,
+		[]models.GoalStatus{models.GoalStatusActive, models.GoalStatusAchieved}).
+		Find(&goals).Error; err != nil {
+		return err
+	}
+	return EvaluateGoals(tx, goals, loc, now)
+}
+
+// goalDeadlineLockKey is the Postgres advisory lock of the goal deadline job
+const goalDeadlineLockKey int64 = 72_617_003
+
+// goalDeadlineBatchSize limits how many goals a single pass of the job settles
+const goalDeadlineBatchSize = 500
+
+// NewGoalDeadlineJob creates the job that settles active goals once their
+// deadline has passed, so they become missed without waiting for a read
+func NewGoalDeadlineJob(db *gorm.DB, interval time.Duration) *PeriodicJob {
+	return NewPeriodicJob(db, "Goal deadline job", goalDeadlineLockKey, interval, func(tx *gorm.DB, now time.Time) (string, error) {
+		settled, err := expireGoals(tx, now)
+		if err != nil || settled == 0 {
+			return "", err
+		}
+		return fmt.Sprintf("settled %d goals past their deadline", settled), nil
+	})
+}
+
+// expireGoals evaluates active goals whose deadline day may have ended before now
+// in their trainer's timezone, so that they become missed without waiting for a
+// read. It returns how many changed status.
+func expireGoals(tx *gorm.DB, now time.Time) (int, error) {
+	var goals []models.Goal
+	if err := tx.Joins("JOIN clients ON clients.id = goals.client_id AND clients.deleted_at IS NULL").
+		Where("goals.status = ? AND goals.deadline <= ?", models.GoalStatusActive, now.UTC().Format("2006-01-02")).
+		Order("goals.deadline ASC").
+		Limit(goalDeadlineBatchSize).
+		Find(&goals).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch goals past their deadline: %w", err)
+	}
+	if len(goals) == 0 {
+		return 0, nil
+	}
+
+	clientIDs := make([]uuid.UUID, 0, len(goals))
+	for _, g := range goals {
+		clientIDs = append(clientIDs, g.ClientID)
+	}
+	var zones []struct {
+		ClientID uuid.UUID
+		Timezone string
+	}
+	if err := tx.Table("clients").
+		Select("clients.id AS client_id, trainers.timezone").
+		Joins("JOIN trainers ON trainers.id = clients.trainer_id").
+		Where("clients.id IN ?", clientIDs).
+		Scan(&zones).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch trainer timezones: %w", err)
+	}
+	locations := make(map[uuid.UUID]*time.Location, len(zones))
+	for _, z := range zones {
+		trainer := models.Trainer{Timezone: z.Timezone}
+		locations[z.ClientID] = trainer.Location()
+	}
+
+	changed := 0
+	for i := range goals {
+		loc, ok := locations[goals[i].ClientID]
+		if !ok {
+			continue
+		}
+		if err := evaluateGoal(tx, &goals[i], loc, now); err != nil {
+			return 0, fmt.Errorf("failed to evaluate goal %s: %w", goals[i].ID, err)
+		}
+		if goals[i].Status != models.GoalStatusActive {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// EvaluateGoals calculates each goal's current value, progress and projection
+// at now, with dates in loc. Active goals become achieved once a value in their
+// window reaches the target, and missed if none has by the end of the deadline day.
+// Achieved goals whose values no longer reach the target go back to active or missed.
+// Values stay in metric.
+func EvaluateGoals(tx *gorm.DB, goals []models.Goal, loc *time.Location, now time.Time) error {
+	for i := range goals {
+		if err := evaluateGoal(tx, &goals[i], loc, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func evaluateGoal(tx *gorm.DB, g *models.Goal, loc *time.Location, now time.Time) error {
+	start := time.Date(g.StartDate.Year(), g.StartDate.Month(), g.StartDate.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(g.Deadline.Year(), g.Deadline.Month(), g.Deadline.Day()+1, 0, 0, 0, 0, loc)
+	daysSince := func(t time.Time) float64 {
+		return t.Sub(start).Hours() / 24
+	}
+
+	// The goal's history starts from its baseline
+	points := []trendPoint{{0, g.Baseline}}
+	var reachedAt *time.Time
+
+	switch g.Kind {
+	case models.GoalKindMeasurement:
+		field, ok := g.MeasurementField()
+		if !ok {
+			return nil
+		}
+		var measurements []models.Measurement
+		if err := tx.Where("client_id = ? AND measured_at >= ? AND measured_at < ?", g.ClientID, start, end).
+			Order("measured_at ASC").
+			Find(&measurements).Error; err != nil {
+			return err
+		}
+		for i := range measurements {
+			if value := field.Get(&measurements[i]); value != nil {
+				points = append(points, trendPoint{daysSince(measurements[i].MeasuredAt), *value})
+				g.Current = value
+				if reachedAt == nil && g.Reached(*value) {
+					reachedAt = &measurements[i].MeasuredAt
+				}
+			}
+		}
+
+	case models.GoalKindSessions:
+		until := now
+		if end.Before(until) {
+			until = end
+		}
+		var completed []time.Time
+		if err := tx.Model(&models.Session{}).
+			Where("client_id = ? AND status = ? AND scheduled_at >= ? AND scheduled_at < ?",
+				g.ClientID, models.SessionStatusCompleted, start, until).
+			Order("scheduled_at ASC").
+			Pluck("scheduled_at", &completed).Error; err != nil {
+			return err
+		}
+		reachedAt = sessionsReachedAt(g, completed)
+		current := float64(len(completed))
+		g.Current = &current
+
+	case models.GoalKindCustom:
+		if g.CustomValue != nil && g.CustomValueAt != nil {
+			points = append(points, trendPoint{daysSince(*g.CustomValueAt), *g.CustomValue})
+			g.Current = g.CustomValue
+			if g.Reached(*g.CustomValue) {
+				reachedAt = g.CustomValueAt
+			}
+		}
+	}
+
+	if g.Current != nil && g.Target != g.Baseline {
+		progress := (*g.Current - g.Baseline) / (g.Target - g.Baseline) * 100
+		progress = math.Round(math.Max(0, math.Min(100, progress))*10) / 10
+		g.ProgressPercent = &progress
+	}
+
+	if g.Status == models.GoalStatusActive || g.Status == models.GoalStatusAchieved {
+		status := models.GoalStatusActive
+		switch {
+		case reachedAt != nil:
+			status = models.GoalStatusAchieved
+		case !now.Before(end):
+			status = models.GoalStatusMissed
+		}
+		if status != g.Status {
+			g.Status = status
+			g.AchievedAt = nil
+			if status == models.GoalStatusAchieved {
+				g.AchievedAt = reachedAt
+			}
+			if err := tx.Model(&models.Goal{}).Where("id = ?", g.ID).Updates(map[string]interface{}{
+				"status":      g.Status,
+				"achieved_at": g.AchievedAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	// Project where the goal is heading by the deadline
+	if g.Status != models.GoalStatusActive {
+		return nil
+	}
+	if g.Kind == models.GoalKindSessions {
+		if elapsed := daysSince(now); elapsed >= 1 {
+			projected := math.Round(*g.Current / elapsed * daysSince(end))
+			g.ProjectedValue = &projected
+		}
+	} else {
+		g.ProjectedValue = linearProjection(points, daysSince(end))
+	}
+	if g.ProjectedValue != nil {
+		onTrack := g.Reached(*g.ProjectedValue)
+		g.OnTrack = &onTrack
+	}
+
+	return nil
+}
+
+// sessionsReachedAt returns when a sessions goal was achieved: the time of the
+// completed session that brought the count to the target, or nil if none has
+func sessionsReachedAt(g *models.Goal, completed []time.Time) *time.Time {
+	for i := range completed {
+		if g.Reached(float64(i + 1)) {
+			return &completed[i]
+		}
+	}
+	return nil
+}
+
+// linearProjection fits a least-squares line through the points and returns its
+// value at the given day, or nil without two points on different days
+func linearProjection(points []trendPoint, at float64) *float64 {
+	if len(points) < 2 {
+		return nil
+	}
+
+	var sumX, sumY, sumXX, sumXY float64
+	for _, p := range points {
+		sumX += p.Days
+		sumY += p.Value
+		sumXX += p.Days * p.Days
+		sumXY += p.Days * p.Value
+	}
+	n := float64(len(points))
+	denominator := n*sumXX - sumX*sumX
+	if math.Abs(denominator) < 1e-9 {
+		return nil
+	}
+
+	slope := (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	projected := intercept + slope*at
+	return &projected
+}
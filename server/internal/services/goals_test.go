@@ -0,0 +1,105 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ptmate/internal/models"
+)
+
+func TestLinearProjection(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []trendPoint
+		at     float64
+		want   *float64
+	}{
+		{
+			name:   "no points",
+			points: nil,
+			at:     30,
+		},
+		{
+			name:   "only the baseline",
+			points: []trendPoint{{0, 90}},
+			at:     30,
+		},
+		{
+			name:   "all points on the same day",
+			points: []trendPoint{{0, 90}, {0, 89}},
+			at:     30,
+		},
+		{
+			name:   "two points extend their line",
+			points: []trendPoint{{0, 90}, {10, 88}},
+			at:     30,
+			want:   float(84),
+		},
+		{
+			name:   "points on a line",
+			points: []trendPoint{{0, 100}, {7, 98.6}, {14, 97.2}, {21, 95.8}},
+			at:     28,
+			want:   float(94.4),
+		},
+		{
+			name:   "least squares through noisy points",
+			points: []trendPoint{{0, 1}, {1, 3}, {2, 2}, {3, 4}},
+			at:     4,
+			want:   float(4.5),
+		},
+		{
+			name:   "flat trend",
+			points: []trendPoint{{0, 20}, {5, 20}, {10, 20}},
+			at:     60,
+			want:   float(20),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := linearProjection(tt.points, tt.at)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("linearProjection() = %v, want nil", *got)
+			case tt.want != nil && got == nil:
+				t.Errorf("linearProjection() = nil, want %v", *tt.want)
+			case tt.want != nil && math.Abs(*got-*tt.want) > 1e-9:
+				t.Errorf("linearProjection() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionsReachedAt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 9, 0, 0, 0, time.UTC) }
+	completed := []time.Time{day(2), day(5), day(9), day(12)}
+
+	tests := []struct {
+		name      string
+		target    float64
+		completed []time.Time
+		want      *time.Time
+	}{
+		{"no sessions", 3, nil, nil},
+		{"short of the target", 5, completed, nil},
+		{"reached by the third session", 3, completed, &completed[2]},
+		{"reached by the last session", 4, completed, &completed[3]},
+		{"fractional target", 2.5, completed, &completed[2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := models.Goal{Kind: models.GoalKindSessions, Baseline: 0, Target: tt.target}
+			got := sessionsReachedAt(&g, tt.completed)
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("sessionsReachedAt() = %v, want nil", *got)
+			case tt.want != nil && got == nil:
+				t.Errorf("sessionsReachedAt() = nil, want %v", *tt.want)
+			case tt.want != nil && !got.Equal(*tt.want):
+				t.Errorf("sessionsReachedAt() = %v, want %v", *got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"ptmate/internal/models"
+)
+
+// Invoice layout, in points
+const (
+	invoiceMargin   = 50.0
+	invoiceBodySize = 10.0
+	invoiceLineGap  = 14.0
+)
+
+// RenderInvoicePDF renders an issued invoice or receipt as a PDF.
+// The issue date is shown in loc, the trainer's timezone. Content that does not
+// fit moves to a new page, repeating the item table header.
+func RenderInvoicePDF(inv models.Invoice, loc *time.Location) []byte {
+	page := NewPDFDocument()
+	left := invoiceMargin
+	right := PageWidth - invoiceMargin
+	y := PageHeight - invoiceMargin
+
+	// fit starts a new page unless height points are left above the bottom margin
+	fit := func(height float64) bool {
+		if y-height >= invoiceMargin {
+			return false
+		}
+		page.AddPage()
+		y = PageHeight - invoiceMargin
+		return true
+	}
+
+	// Header: seller on the left, document details on the right
+	page.Text(left, y-8, 16, true, inv.SellerName)
+	page.TextRight(right, y-8, 20, true, inv.Title())
+	y -= 30
+
+	detailsY := y
+	for _, line := range sellerLines(inv) {
+		page.Text(left, y, invoiceBodySize, false, line)
+		y -= invoiceLineGap
+	}
+
+	page.TextRight(right, detailsY, invoiceBodySize, true, "No: "+inv.Number)
+	page.TextRight(right, detailsY-invoiceLineGap, invoiceBodySize, false, "Date: "+inv.IssuedAt.In(loc).Format("02.01.2006"))
+
+	// Buyer
+	y -= 20
+	page.Text(left, y, invoiceBodySize, true, "Bill to")
+	y -= invoiceLineGap
+	for _, line := range []string{inv.BuyerName, inv.BuyerEmail, inv.BuyerPhone} {
+		if line == "" {
+			continue
+		}
+		page.Text(left, y, invoiceBodySize, false, line)
+		y -= invoiceLineGap
+	}
+
+	// Line items
+	y -= 20
+	qtyX := right - 200
+	unitX := right - 100
+	tableHeader := func() {
+		page.FillRect(left, y-6, right-left, 20, 0.9)
+		page.Text(left+6, y, invoiceBodySize, true, "Description")
+		page.TextRight(qtyX, y, invoiceBodySize, true, "Qty")
+		page.TextRight(unitX, y, invoiceBodySize, true, "Unit price")
+		page.TextRight(right-6, y, invoiceBodySize, true, "Amount")
+		y -= 24
+	}
+	fit(24 + invoiceLineGap)
+	tableHeader()
+
+	for _, item := range inv.Items {
+		lines := WrapText(item.Description, qtyX-left-60, invoiceBodySize)
+		if fit(invoiceLineGap) {
+			tableHeader()
+		}
+		page.TextRight(qtyX, y, invoiceBodySize, false, strconv.Itoa(item.Quantity))
+		page.TextRight(unitX, y, invoiceBodySize, false, FormatMoney(item.UnitPrice, inv.Currency))
+		page.TextRight(right-6, y, invoiceBodySize, false, FormatMoney(item.Total, inv.Currency))
+		for i, line := range lines {
+			if i > 0 && fit(invoiceLineGap) {
+				tableHeader()
+			}
+			page.Text(left+6, y, invoiceBodySize, false, line)
+			y -= invoiceLineGap
+		}
+		y -= 4
+	}
+	page.Line(left, y+6, right, y+6, 0.5)
+
+	// Totals
+	totals := []struct {
+		label  string
+		amount float64
+		bold   bool
+	}{
+		{"Subtotal", inv.Subtotal, false},
+		{"Tax (" + formatPercent(inv.TaxRate) + ")", inv.TaxAmount, false},
+		{"Total", inv.Total, true},
+	}
+	y -= 12
+	fit(float64(len(totals)) * (invoiceLineGap + 2))
+	for _, t := range totals {
+		page.TextRight(unitX, y, invoiceBodySize, t.bold, t.label)
+		page.TextRight(right-6, y, invoiceBodySize, t.bold, FormatMoney(t.amount, inv.Currency))
+		y -= invoiceLineGap + 2
+	}
+
+	// Notes
+	if inv.Notes != "" {
+		y -= 20
+		fit(2 * invoiceLineGap)
+		page.Text(left, y, invoiceBodySize, true, "Notes")
+		y -= invoiceLineGap
+		for _, line := range WrapText(inv.Notes, right-left, invoiceBodySize) {
+			fit(invoiceLineGap)
+			page.Text(left, y, invoiceBodySize, false, line)
+			y -= invoiceLineGap
+		}
+	}
+
+	return page.Bytes()
+}
+
+// sellerLines returns the seller's contact and tax details, skipping empty ones
+func sellerLines(inv models.Invoice) []string {
+	var lines []string
+	lines = append(lines, WrapText(inv.SellerAddress, 250, invoiceBodySize)...)
+	if inv.SellerPhone != "" {
+		lines = append(lines, inv.SellerPhone)
+	}
+	if inv.SellerEmail != "" {
+		lines = append(lines, inv.SellerEmail)
+	}
+	var tax []string
+	if inv.SellerTaxOffice != "" {
+		tax = append(tax, "Tax office: "+inv.SellerTaxOffice)
+	}
+	if inv.SellerTaxNumber != "" {
+		tax = append(tax, "Tax no: "+inv.SellerTaxNumber)
+	}
+	if len(tax) > 0 {
+		lines = append(lines, strings.Join(tax, "  "))
+	}
+
+	// Drop blank lines left by an empty address
+	filtered := lines[:0]
+	for _, line := range lines {
+		if line != "" {
+			filtered = append(filtered, line)
+		}
+	}
+	return filtered
+}
+
+// FormatMoney formats an amount with thousands separators, e.g. "1,234.50 TRY"
+func FormatMoney(amount float64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+	for i := len(whole) - 3; i > 0; i -= 3 {
+		whole = whole[:i] + "," + whole[i:]
+	}
+
+	return fmt.Sprintf("%s%s.%02d %s", sign, whole, cents%100, currency)
+}
+
+// formatPercent formats a rate without trailing zeros, e.g. "20%" or "8.5%"
+func formatPercent(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "%"
+}
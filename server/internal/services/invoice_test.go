@@ -0,0 +1,92 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"ptmate/internal/models"
+)
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     string
+	}{
+		{0, "TRY", "0.00 TRY"},
+		{9.5, "EUR", "9.50 EUR"},
+		{999.999, "USD", "1,000.00 USD"},
+		{1234.5, "TRY", "1,234.50 TRY"},
+		{1234567.89, "TRY", "1,234,567.89 TRY"},
+		{-1500, "TRY", "-1,500.00 TRY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatMoney(tt.amount, tt.currency); got != tt.want {
+				t.Errorf("FormatMoney(%v, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvoiceNumber(t *testing.T) {
+	if got := models.InvoiceNumber(42); got != "INV-000042" {
+		t.Errorf("InvoiceNumber(42) = %q, want INV-000042", got)
+	}
+}
+
+func TestRenderInvoicePDF(t *testing.T) {
+	inv := models.Invoice{
+		Kind:       models.InvoiceKindInvoice,
+		Number:     models.InvoiceNumber(7),
+		IssuedAt:   time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC),
+		SellerName: "Ayşe Yılmaz",
+		BuyerName:  "Mehmet Demir",
+		Items:      []models.InvoiceItem{{Description: "10 sessions", Quantity: 1, UnitPrice: 5000, Total: 5000}},
+		Currency:   "TRY",
+		Subtotal:   5000,
+		TaxRate:    20,
+		TaxAmount:  1000,
+		Total:      6000,
+	}
+
+	pdf := RenderInvoicePDF(inv, time.UTC)
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.HasSuffix(bytes.TrimSpace(pdf), []byte("%%EOF")) {
+		t.Fatal("RenderInvoicePDF() did not produce a complete PDF")
+	}
+	if pages := bytes.Count(pdf, []byte("/Type /Page ")); pages != 1 {
+		t.Errorf("short invoice has %d pages, want 1", pages)
+	}
+
+	for i := 0; i < 80; i++ {
+		inv.Items = append(inv.Items, models.InvoiceItem{Description: fmt.Sprintf("Session %d", i+1), Quantity: 1, UnitPrice: 500, Total: 500})
+	}
+	if pages := bytes.Count(RenderInvoicePDF(inv, time.UTC), []byte("/Type /Page ")); pages < 2 {
+		t.Errorf("invoice with 81 items has %d pages, want the items to continue on a new page", pages)
+	}
+}
+
+func TestRenderInvoicePDFDate(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	// Late in the evening UTC is already the next day in Istanbul
+	inv := models.Invoice{
+		Kind:     models.InvoiceKindInvoice,
+		Number:   models.InvoiceNumber(1),
+		IssuedAt: time.Date(2026, time.March, 1, 22, 30, 0, 0, time.UTC),
+		Currency: "TRY",
+	}
+
+	if pdf := RenderInvoicePDF(inv, istanbul); !bytes.Contains(pdf, []byte("Date: 02.03.2026")) {
+		t.Error("invoice date is not shown in the trainer's timezone")
+	}
+	if pdf := RenderInvoicePDF(inv, time.UTC); !bytes.Contains(pdf, []byte("Date: 01.03.2026")) {
+		t.Error("invoice date is not shown in UTC")
+	}
+}
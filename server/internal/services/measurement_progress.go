@@ -0,0 +1,81 @@
+package services
+
+import (
+	"math"
+
+	"ptmate/internal/models"
+)
+
+// MeasurementProgress builds the progress of each measurement field that has
+// any entries, from measurements in ascending order, with values in system
+func MeasurementProgress(measurements []models.Measurement, system models.UnitSystem) []models.FieldProgress {
+	progress := make([]models.FieldProgress, 0, len(models.MeasurementFields))
+	for _, field := range models.MeasurementFields {
+		var points []models.MeasurementPoint
+		var ema float64
+		for i := range measurements {
+			value := field.Get(&measurements[i])
+			if value == nil {
+				continue
+			}
+
+			point := models.MeasurementPoint{
+				MeasurementID: measurements[i].ID,
+				MeasuredAt:    measurements[i].MeasuredAt,
+				Value:         system.FromMetric(field.Dimension, *value),
+			}
+			if len(points) == 0 {
+				ema = point.Value
+			} else {
+				ema += models.TrendSmoothing * (point.Value - ema)
+			}
+			point.Trend = roundDelta(ema)
+			points = append(points, point)
+		}
+		if len(points) == 0 {
+			continue
+		}
+
+		latest := points[len(points)-1]
+		p := models.FieldProgress{
+			Field:  field.Key,
+			Unit:   system.Unit(field.Dimension),
+			Latest: latest.Value,
+			Trend:  latest.Trend,
+			Points: points,
+		}
+		if len(points) > 1 {
+			p.ChangeSincePrevious = delta(points[len(points)-2].Value, latest.Value)
+			p.ChangeSinceFirst = delta(points[0].Value, latest.Value)
+		}
+		p.Change30Days = changeOver(points, models.ProgressShortWindowDays)
+		p.Change90Days = changeOver(points, models.ProgressLongWindowDays)
+		progress = append(progress, p)
+	}
+	return progress
+}
+
+// changeOver compares the latest point with the last point at least days days
+// before it, or returns nil if there is none
+func changeOver(points []models.MeasurementPoint, days int) *float64 {
+	latest := points[len(points)-1]
+	cutoff := latest.MeasuredAt.AddDate(0, 0, -days)
+	for i := len(points) - 2; i >= 0; i-- {
+		if !points[i].MeasuredAt.After(cutoff) {
+			return delta(points[i].Value, latest.Value)
+		}
+	}
+	return nil
+}
+
+// delta returns to - from, rounded like the values
+func delta(from, to float64) *float64 {
+	d := roundDelta(to - from)
+	return &d
+}
+
+// roundDelta rounds to the decimals measured values are returned with
+func roundDelta(value float64) float64 {
+	scale := math.Pow10(models.UnitDecimals)
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"ptmate/internal/models"
+)
+
+func TestMeasurementProgress(t *testing.T) {
+	on := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 8, 0, 0, 0, time.UTC)
+	}
+	measurements := []models.Measurement{
+		{MeasuredAt: on(time.January, 1), WeightKg: float(80), HeightCm: float(180)},
+		{MeasuredAt: on(time.January, 20), WeightKg: float(79)},
+		{MeasuredAt: on(time.February, 10), WeightKg: float(78)},
+		{MeasuredAt: on(time.April, 25), WeightKg: float(77.5)},
+	}
+
+	progress := MeasurementProgress(measurements, models.UnitSystemMetric)
+	byField := map[string]models.FieldProgress{}
+	for _, p := range progress {
+		byField[p.Field] = p
+	}
+	if len(byField) != 2 {
+		t.Fatalf("got progress for %d fields, want only weight and height", len(byField))
+	}
+
+	weight := byField["weight"]
+	wantTrends := []float64{80, 79.7, 79.19, 78.68}
+	for i, point := range weight.Points {
+		if point.Trend != wantTrends[i] {
+			t.Errorf("trend at entry %d = %v, want %v", i, point.Trend, wantTrends[i])
+		}
+	}
+	checks := []struct {
+		name string
+		got  *float64
+		want float64
+	}{
+		{"change since previous", weight.ChangeSincePrevious, -0.5},
+		{"change since first", weight.ChangeSinceFirst, -2.5},
+		{"change over 30 days", weight.Change30Days, -0.5},
+		{"change over 90 days", weight.Change90Days, -1.5},
+	}
+	for _, c := range checks {
+		if c.got == nil || *c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if weight.Latest != 77.5 || weight.Trend != 78.68 {
+		t.Errorf("latest %v, trend %v, want 77.5 and 78.68", weight.Latest, weight.Trend)
+	}
+
+	height := byField["height"]
+	if height.Latest != 180 || height.ChangeSincePrevious != nil || height.Change30Days != nil {
+		t.Errorf("single height entry gave %+v, want no changes", height)
+	}
+}
+
+func float(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// noShowLockKey is the Postgres advisory lock that lets only one replica run a pass at a time
+const noShowLockKey int64 = 72_617_001
+
+// noShowBatchSize limits how many sessions a single pass handles per policy
+const noShowBatchSize = 500
+
+// NoShowWorker resolves finished sessions whose attendance was never recorded
+type NoShowWorker struct {
+	db       *gorm.DB
+	grace    time.Duration
+	interval time.Duration
+}
+
+// NewNoShowWorker creates a new NoShowWorker
+func NewNoShowWorker(db *gorm.DB, grace, interval time.Duration) *NoShowWorker {
+	return &NoShowWorker{db: db, grace: grace, interval: interval}
+}
+
+// Start runs the worker in the background until ctx is cancelled
+func (w *NoShowWorker) Start(ctx context.Context) {
+	if w.interval <= 0 {
+		log.Println("No-show worker disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.runLogged()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runLogged runs a single pass and logs its outcome
+func (w *NoShowWorker) runLogged() {
+	marked, flagged, err := w.RunOnce(time.Now())
+	if err != nil {
+		log.Printf("No-show worker failed: %v", err)
+		return
+	}
+	if marked > 0 || flagged > 0 {
+		log.Printf("No-show worker marked %d sessions as no-show and flagged %d for attendance", marked, flagged)
+	}
+}
+
+// RunOnce handles every session that ended more than the grace period before now.
+// It does nothing if another replica holds the lock.
+func (w *NoShowWorker) RunOnce(now time.Time) (marked, flagged int, err error) {
+	cutoff := now.Add(-w.grace)
+
+	err = w.db.Transaction(func(tx *gorm.DB) error {
+		// Released automatically when the transaction ends
+		var acquired bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", noShowLockKey).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if !acquired {
+			return nil
+		}
+
+		toMark, err := overdueSessions(tx, models.NoShowPolicyMark, cutoff)
+		if err != nil {
+			return err
+		}
+		if err := markNoShow(tx, toMark); err != nil {
+			return err
+		}
+
+		toFlag, err := overdueSessions(tx, models.NoShowPolicyFlag, cutoff)
+		if err != nil {
+			return err
+		}
+		if err := flagNeedsAttendance(tx, toFlag); err != nil {
+			return err
+		}
+
+		marked, flagged = len(toMark), len(toFlag)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return marked, flagged, nil
+}
+
+// overdueSessions returns unflagged scheduled sessions that ended before cutoff
+// for trainers with the given no-show policy
+func overdueSessions(tx *gorm.DB, policy string, cutoff time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := tx.Joins("JOIN clients ON clients.id = sessions.client_id AND clients.deleted_at IS NULL").
+		Joins("JOIN trainers ON trainers.id = clients.trainer_id AND trainers.deleted_at IS NULL").
+		Where("trainers.no_show_policy = ?", policy).
+		Where("sessions.status = ? AND sessions.needs_attendance = ?", models.SessionStatusScheduled, false).
+		Where("sessions.scheduled_at + sessions.duration_minutes * INTERVAL '1 minute' < ?", cutoff).
+		Order("sessions.scheduled_at ASC").
+		Limit(noShowBatchSize).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch overdue sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// markNoShow moves sessions to no_show and records the change as a system action
+func markNoShow(tx *gorm.DB, sessions []models.Session) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	events := make([]models.SessionStatusEvent, 0, len(sessions))
+	for i := range sessions {
+		from := sessions[i].Status
+		sessions[i].Status = models.SessionStatusNoShow
+		if err := AssignPackage(tx, &sessions[i]); err != nil {
+			return fmt.Errorf("failed to assign package for session %s: %w", sessions[i].ID, err)
+		}
+		if err := tx.Save(&sessions[i]).Error; err != nil {
+			return fmt.Errorf("failed to mark session %s as no-show: %w", sessions[i].ID, err)
+		}
+		events = append(events, models.NewStatusEvent(sessions[i], from, models.SystemActor(), "Attendance was not recorded"))
+	}
+
+	return tx.Create(&events).Error
+}
+
+// flagNeedsAttendance flags sessions for the trainer to mark by hand
+func flagNeedsAttendance(tx *gorm.DB, sessions []models.Session) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(sessions))
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+
+	// UpdateColumn skips the save hook that would clear the flag
+	return tx.Model(&models.Session{}).Where("id IN ?", ids).UpdateColumn("needs_attendance", true).Error
+}
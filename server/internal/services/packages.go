@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PackageBalance is a client's packages with their usage and the total sessions left
+type PackageBalance struct {
+	Packages []models.PackageResponse
+	// Unassigned counts used sessions no package could cover
+	Unassigned int
+	Remaining  int
+}
+
+// AssignPackage keeps a session's package in line with its status. A used session
+// takes a session from the client's oldest active package that had started by then
+// and still has room; a session that no longer counts as used gives it back.
+func AssignPackage(tx *gorm.DB, session *models.Session) error {
+	if !session.IsUsed() {
+		session.PackageID = nil
+		return nil
+	}
+	if session.PackageID != nil {
+		return nil
+	}
+
+	used := tx.Model(&models.Session{}).
+		Select("COUNT(*)").
+		Where("sessions.package_id = packages.id AND sessions.status IN ?", models.UsedStatuses())
+
+	// Lock the package so concurrent updates cannot overfill it
+	var pkg models.Package
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("client_id = ? AND status = ?", session.ClientID, models.PackageStatusActive).
+		Where("starts_at <= ?", session.ScheduledAt).
+		Where("expires_at IS NULL OR expires_at > ?", session.ScheduledAt).
+		Where("session_count > (?)", used).
+		Order("starts_at ASC, purchased_at ASC").
+		First(&pkg).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Nothing left to take from; the session is reported as unassigned
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	session.PackageID = &pkg.ID
+	return nil
+}
+
+// packageStartLockKey is the Postgres advisory lock of the package start job
+const packageStartLockKey int64 = 72_617_002
+
+// packageStartBatchSize limits how many packages a single pass of the job fills
+const packageStartBatchSize = 500
+
+// BackfillPackage gives a package that has started and still has room the client's
+// used sessions that no package covered when they were used, oldest first.
+// It returns how many sessions it assigned.
+func BackfillPackage(tx *gorm.DB, pkg models.Package, now time.Time) (int, error) {
+	if pkg.Status != models.PackageStatusActive || pkg.StartsAt.After(now) || pkg.IsExpired(now) {
+		return 0, nil
+	}
+
+	// Lock the package like AssignPackage so concurrent updates cannot overfill it
+	var locked models.Package
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&locked, "id = ?", pkg.ID).Error; err != nil {
+		return 0, err
+	}
+
+	var used int64
+	if err := tx.Model(&models.Session{}).
+		Where("package_id = ? AND status IN ?", pkg.ID, models.UsedStatuses()).
+		Count(&used).Error; err != nil {
+		return 0, err
+	}
+	room := pkg.SessionCount - int(used)
+	if room <= 0 {
+		return 0, nil
+	}
+
+	var ids []uuid.UUID
+	if err := tx.Model(&models.Session{}).
+		Where("client_id = ? AND package_id IS NULL AND status IN ?", pkg.ClientID, models.UsedStatuses()).
+		Order("scheduled_at ASC").
+		Limit(room).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := tx.Model(&models.Session{}).Where("id IN ?", ids).UpdateColumn("package_id", pkg.ID).Error; err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// NewPackageStartJob creates the job that backfills packages once they start,
+// for packages sold ahead of their start date to clients with unassigned sessions
+func NewPackageStartJob(db *gorm.DB, interval time.Duration) *PeriodicJob {
+	return NewPeriodicJob(db, "Package start job", packageStartLockKey, interval, func(tx *gorm.DB, now time.Time) (string, error) {
+		assigned, err := backfillStartedPackages(tx, now)
+		if err != nil || assigned == 0 {
+			return "", err
+		}
+		return fmt.Sprintf("assigned %d unassigned sessions to started packages", assigned), nil
+	})
+}
+
+// backfillStartedPackages backfills started packages of clients that have
+// unassigned used sessions, oldest package first
+func backfillStartedPackages(tx *gorm.DB, now time.Time) (int, error) {
+	unassigned := tx.Model(&models.Session{}).
+		Select("1").
+		Where("sessions.client_id = packages.client_id AND sessions.package_id IS NULL AND sessions.status IN ?", models.UsedStatuses())
+	used := tx.Model(&models.Session{}).
+		Select("COUNT(*)").
+		Where("sessions.package_id = packages.id AND sessions.status IN ?", models.UsedStatuses())
+
+	var packages []models.Package
+	if err := tx.Where("status = ? AND starts_at <= ?", models.PackageStatusActive, now).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Where("session_count > (?)", used).
+		Where("EXISTS (?)", unassigned).
+		Order("starts_at ASC, purchased_at ASC").
+		Limit(packageStartBatchSize).
+		Find(&packages).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch started packages: %w", err)
+	}
+
+	assigned := 0
+	for _, pkg := range packages {
+		n, err := BackfillPackage(tx, pkg, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to backfill package %s: %w", pkg.ID, err)
+		}
+		assigned += n
+	}
+	return assigned, nil
+}
+
+// LoadPackageBalance returns a client's packages, oldest first, with their usage
+func LoadPackageBalance(db *gorm.DB, clientID uuid.UUID, now time.Time) (PackageBalance, error) {
+	var balance PackageBalance
+
+	var packages []models.Package
+	if err := db.Preload("Freezes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at ASC")
+	}).Where("client_id = ?", clientID).
+		Order("starts_at ASC, purchased_at ASC").
+		Find(&packages).Error; err != nil {
+		return balance, err
+	}
+
+	var usage []struct {
+		PackageID *uuid.UUID
+		Used      int
+	}
+	if err := db.Model(&models.Session{}).
+		Select("package_id, COUNT(*) as used").
+		Where("client_id = ? AND status IN ?", clientID, models.UsedStatuses()).
+		Group("package_id").
+		Scan(&usage).Error; err != nil {
+		return balance, err
+	}
+
+	usedByPackage := make(map[uuid.UUID]int, len(usage))
+	for _, u := range usage {
+		if u.PackageID == nil {
+			balance.Unassigned = u.Used
+			continue
+		}
+		usedByPackage[*u.PackageID] = u.Used
+	}
+
+	balance.Packages = make([]models.PackageResponse, 0, len(packages))
+	for _, pkg := range packages {
+		response := PackageUsage(pkg, usedByPackage[pkg.ID], now)
+		if pkg.CountsTowardBalance(now) {
+			balance.Remaining += response.RemainingSessions
+		}
+		balance.Packages = append(balance.Packages, response)
+	}
+	balance.Remaining -= balance.Unassigned
+
+	return balance, nil
+}
+
+// PackageUsage builds the response for a package that used the given number of sessions
+func PackageUsage(pkg models.Package, used int, now time.Time) models.PackageResponse {
+	remaining := pkg.SessionCount - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return models.PackageResponse{
+		Package:           pkg,
+		UsedSessions:      used,
+		RemainingSessions: remaining,
+		Expired:           pkg.IsExpired(now),
+	}
+}
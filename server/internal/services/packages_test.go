@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"ptmate/internal/models"
+)
+
+func TestPackageUsage(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		pkg           models.Package
+		used          int
+		wantRemaining int
+		wantExpired   bool
+	}{
+		{"unused", models.Package{SessionCount: 10}, 0, 10, false},
+		{"partly used", models.Package{SessionCount: 10}, 4, 6, false},
+		{"used up", models.Package{SessionCount: 10}, 10, 0, false},
+		{"overused", models.Package{SessionCount: 10}, 12, 0, false},
+		{"expired", models.Package{SessionCount: 10, ExpiresAt: &expired}, 3, 7, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PackageUsage(tt.pkg, tt.used, now)
+			if got.UsedSessions != tt.used || got.RemainingSessions != tt.wantRemaining || got.Expired != tt.wantExpired {
+				t.Errorf("PackageUsage() = used %d, remaining %d, expired %v, want %d, %d, %v",
+					got.UsedSessions, got.RemainingSessions, got.Expired, tt.used, tt.wantRemaining, tt.wantExpired)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size in PDF points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// pdfEncoding extends WinAnsiEncoding with the Turkish letters it lacks.
+// The codes replaced are unused (or rarely used) in WinAnsi.
+var pdfEncoding = []struct {
+	code  byte
+	glyph string
+	char  rune
+}{
+	{0x81, "Gbreve", 'Ğ'},
+	{0x8D, "gbreve", 'ğ'},
+	{0x8F, "Scedilla", 'Ş'},
+	{0x90, "scedilla", 'ş'},
+	{0x9D, "Idotaccent", 'İ'},
+	{0x9E, "dotlessi", 'ı'},
+}
+
+// winAnsiHigh maps the non-Latin-1 characters of WinAnsiEncoding to their codes
+var winAnsiHigh = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'Ÿ': 0x9F,
+}
+
+// helveticaWidths are the Helvetica glyph widths (per 1000 units) for ASCII 32-126
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// PDFDocument is a minimal PDF writer using the built-in Helvetica fonts, so
+// documents can be rendered without any external tools. It draws on its last
+// page; coordinates are in points from the bottom-left corner of an A4 page.
+type PDFDocument struct {
+	pages []*bytes.Buffer
+}
+
+// NewPDFDocument creates a document with one empty A4 page
+func NewPDFDocument() *PDFDocument {
+	return &PDFDocument{pages: []*bytes.Buffer{{}}}
+}
+
+// AddPage starts a new empty page that the following calls draw on
+func (d *PDFDocument) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// Text draws text with its baseline starting at (x, y)
+func (d *PDFDocument) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDFString(encodePDFText(text)))
+}
+
+// TextRight draws text so that it ends at x
+func (d *PDFDocument) TextRight(x, y, size float64, bold bool, text string) {
+	d.Text(x-TextWidth(text, size), y, size, bold, text)
+}
+
+// Line draws a straight line
+func (d *PDFDocument) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// FillRect fills a rectangle with a gray level between 0 (black) and 1 (white)
+func (d *PDFDocument) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.page(), "q %.2f g %.2f %.2f %.2f %.2f re f Q\n", gray, x, y, w, h)
+}
+
+// Bytes returns the complete PDF file
+func (d *PDFDocument) Bytes() []byte {
+	var differences strings.Builder
+	for _, e := range pdfEncoding {
+		fmt.Fprintf(&differences, "%d /%s ", e.code, e.glyph)
+	}
+
+	// The shared objects come first; each page then takes two objects, itself and its content
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding 5 0 R >>",
+		fmt.Sprintf("<< /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [%s] >>", strings.TrimSpace(differences.String())),
+	}
+	for i, content := range d.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", PageWidth, PageHeight, firstPage+2*i+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// page returns the content of the page being drawn on
+func (d *PDFDocument) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// TextWidth returns the width of text in points when set in Helvetica
+func TextWidth(text string, size float64) float64 {
+	units := 0
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// WrapText splits text into lines that fit within width points
+func WrapText(text string, width, size float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(candidate, size) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// encodePDFText converts UTF-8 text to the single-byte font encoding.
+// Characters the fonts cannot show are replaced with '?'.
+func encodePDFText(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r < 0x80:
+			out = append(out, byte(r))
+		case r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if code, ok := winAnsiHigh[r]; ok {
+				out = append(out, code)
+				continue
+			}
+			code := byte('?')
+			for _, e := range pdfEncoding {
+				if e.char == r {
+					code = e.code
+					break
+				}
+			}
+			out = append(out, code)
+		}
+	}
+	return out
+}
+
+// escapePDFString escapes the characters that are special inside a PDF string literal
+func escapePDFString(b []byte) string {
+	var out strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			out.WriteByte('\\')
+			out.WriteByte(c)
+		case '\n', '\r':
+			out.WriteByte(' ')
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PeriodicJob runs a task in the background at a fixed interval. Each pass runs in
+// a transaction holding a Postgres advisory lock, so only one replica runs the task
+// at a time.
+type PeriodicJob struct {
+	db       *gorm.DB
+	name     string
+	lockKey  int64
+	interval time.Duration
+	// run does one pass and returns what it did for the log, or "" if nothing
+	run func(tx *gorm.DB, now time.Time) (string, error)
+}
+
+// NewPeriodicJob creates a new PeriodicJob. An interval of 0 disables it.
+func NewPeriodicJob(db *gorm.DB, name string, lockKey int64, interval time.Duration, run func(tx *gorm.DB, now time.Time) (string, error)) *PeriodicJob {
+	return &PeriodicJob{db: db, name: name, lockKey: lockKey, interval: interval, run: run}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *PeriodicJob) Start(ctx context.Context) {
+	if j.interval <= 0 {
+		log.Printf("%s disabled", j.name)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.runLogged()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runLogged runs a single pass and logs its outcome
+func (j *PeriodicJob) runLogged() {
+	done, err := j.RunOnce(time.Now())
+	if err != nil {
+		log.Printf("%s failed: %v", j.name, err)
+		return
+	}
+	if done != "" {
+		log.Printf("%s %s", j.name, done)
+	}
+}
+
+// RunOnce runs a single pass. It does nothing if another replica holds the lock.
+func (j *PeriodicJob) RunOnce(now time.Time) (string, error) {
+	var done string
+	err := j.db.Transaction(func(tx *gorm.DB) error {
+		// Released automatically when the transaction ends
+		var acquired bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", j.lockKey).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if !acquired {
+			return nil
+		}
+
+		var err error
+		done, err = j.run(tx, now)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return done, nil
+}
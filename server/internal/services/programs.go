@@ -0,0 +1,49 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// programDayQuery finds the day of a client's assigned programs that falls on a
+// session's date in the trainer's timezone. The two placeholders are the client
+// ID and the session start. Newer programs win when assignments overlap.
+const programDayQuery = `SELECT program_days.id FROM program_days
+	JOIN program_weeks ON program_weeks.id = program_days.program_week_id
+	JOIN programs ON programs.id = program_weeks.program_id AND programs.deleted_at IS NULL
+	JOIN trainers ON trainers.id = programs.trainer_id
+	WHERE programs.client_id = %s
+		AND programs.starts_on + ((program_weeks.number - 1) * 7 + program_days.day - 1) = (%s AT TIME ZONE trainers.timezone)::date
+	ORDER BY programs.starts_on DESC, programs.created_at DESC
+	LIMIT 1`
+
+// MatchProgramDay links a session to the program day on its date, or unlinks it
+// when the client has no program day then. The session is not saved.
+func MatchProgramDay(tx *gorm.DB, session *models.Session) error {
+	var ids []uuid.UUID
+	if err := tx.Raw(fmt.Sprintf(programDayQuery, "?", "?::timestamptz"), session.ClientID, session.ScheduledAt).
+		Scan(&ids).Error; err != nil {
+		return err
+	}
+
+	session.ProgramDayID = nil
+	if len(ids) > 0 {
+		session.ProgramDayID = &ids[0]
+	}
+	return nil
+}
+
+// RelinkProgramSessions re-matches a client's upcoming sessions from from on to
+// their program days, after a program is assigned, changed or deleted. Sessions
+// that already took place keep the day they were trained on.
+func RelinkProgramSessions(tx *gorm.DB, clientID uuid.UUID, from time.Time) error {
+	return tx.Exec("UPDATE sessions SET program_day_id = ("+
+		fmt.Sprintf(programDayQuery, "sessions.client_id", "sessions.scheduled_at")+
+		") WHERE client_id = ? AND status IN ? AND scheduled_at >= ? AND deleted_at IS NULL",
+		clientID, []models.SessionStatus{models.SessionStatusPending, models.SessionStatusScheduled}, from).Error
+}
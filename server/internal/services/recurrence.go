@@ -0,0 +1,261 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences caps how many occurrences a single rule may expand into
+const MaxOccurrences = 500
+
+// RRule is the subset of an iCalendar (RFC 5545) recurrence rule we support:
+// FREQ=DAILY|WEEKLY with INTERVAL, BYDAY, COUNT and UNTIL
+type RRule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    *time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// ParseRRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=36".
+// A floating or date-only UNTIL is read as local time in loc.
+func ParseRRule(value string, loc *time.Location) (*RRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("rrule is empty")
+	}
+
+	rule := &RRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rrule part %q", part)
+		}
+		key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			if val != "DAILY" && val != "WEEKLY" {
+				return nil, fmt.Errorf("unsupported FREQ %q (only DAILY and WEEKLY)", val)
+			}
+			rule.Freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseICalTime(val, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q", code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "WKST":
+			if val != "MO" {
+				return nil, fmt.Errorf("only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rrule part %q", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("FREQ is required")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("COUNT and UNTIL cannot be used together")
+	}
+
+	return rule, nil
+}
+
+// String renders the rule back into RRULE syntax
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			for code, d := range weekdayCodes {
+				if d == day {
+					codes = append(codes, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Expand returns the occurrences of the rule starting at dtstart, in dtstart's
+// location. Occurrences keep dtstart's wall-clock time, so dtstart should be in the
+// trainer's timezone rather than a fixed offset for sessions to stay at the same
+// local time across daylight saving changes. Dates in exdates are skipped but still
+// count towards COUNT, as in RFC 5545. If limit is positive, expansion stops after
+// that many occurrences.
+func (r *RRule) Expand(dtstart time.Time, exdates []time.Time, limit int) []time.Time {
+	if limit <= 0 || limit > MaxOccurrences {
+		limit = MaxOccurrences
+	}
+
+	excluded := make(map[int64]bool, len(exdates))
+	for _, ex := range exdates {
+		excluded[ex.Unix()] = true
+	}
+
+	days := r.ByDay
+	if len(days) == 0 {
+		days = []time.Weekday{dtstart.Weekday()}
+	}
+	// Order weekdays Monday-first so they follow WKST=MO
+	sorted := append([]time.Weekday(nil), days...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return (sorted[i]+6)%7 < (sorted[j]+6)%7
+	})
+
+	loc := dtstart.Location()
+	hour, min, sec := dtstart.Clock()
+	occurrence := func(base time.Time, offset int) time.Time {
+		return time.Date(base.Year(), base.Month(), base.Day()+offset, hour, min, sec, 0, loc)
+	}
+
+	var result []time.Time
+	generated := 0
+	emit := func(t time.Time) bool {
+		if t.Before(dtstart) {
+			return true
+		}
+		if r.Until != nil && t.After(*r.Until) {
+			return false
+		}
+		if r.Count > 0 && generated >= r.Count {
+			return false
+		}
+		generated++
+		if !excluded[t.Unix()] {
+			result = append(result, t)
+		}
+		return len(result) < limit
+	}
+
+	switch r.Freq {
+	case "DAILY":
+		allowed := make(map[time.Weekday]bool, len(r.ByDay))
+		for _, day := range r.ByDay {
+			allowed[day] = true
+		}
+		for i := 0; generated < MaxOccurrences && i < MaxOccurrences*7*r.Interval; i += r.Interval {
+			t := occurrence(dtstart, i)
+			if len(allowed) > 0 && !allowed[t.Weekday()] {
+				continue
+			}
+			if !emit(t) {
+				break
+			}
+		}
+	case "WEEKLY":
+		weekStart := occurrence(dtstart, -int((dtstart.Weekday()+6)%7))
+	weeks:
+		for week := 0; generated < MaxOccurrences; week += r.Interval {
+			for _, day := range sorted {
+				t := occurrence(weekStart, week*7+int((day+6)%7))
+				if !emit(t) {
+					break weeks
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// SplitAt divides the rule of a series starting at dtstart into the occurrences
+// before at and the ones from at on. The first rule ends with UNTIL just before at,
+// and the second keeps what is left of COUNT, or the original UNTIL.
+func (r *RRule) SplitAt(dtstart, at time.Time) (*RRule, *RRule) {
+	before := *r
+	before.Count = 0
+	until := at.Add(-time.Second)
+	before.Until = &until
+
+	after := *r
+	if r.Count > 0 {
+		earlier := 0
+		for _, t := range r.Expand(dtstart, nil, 0) {
+			if t.Before(at) {
+				earlier++
+			}
+		}
+		after.Count = max(r.Count-earlier, 1)
+	}
+	return &before, &after
+}
+
+// ShiftDays moves the rule's BYDAY weekdays by a number of days, for occurrences
+// that were moved to other days of the week
+func (r *RRule) ShiftDays(days int) {
+	shifted := make([]time.Weekday, 0, len(r.ByDay))
+	for _, day := range r.ByDay {
+		shifted = append(shifted, time.Weekday(((int(day)+days)%7+7)%7))
+	}
+	r.ByDay = shifted
+}
+
+// parseICalTime accepts the iCalendar DATE-TIME/DATE forms and RFC 3339.
+// Forms without a UTC marker or offset are local time in loc.
+func parseICalTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		// A bare UNTIL date includes the whole day
+		return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, loc), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date-time %q", value)
+}
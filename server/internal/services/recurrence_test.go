@@ -0,0 +1,226 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRRuleExpand(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	date := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, newYork)
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		exdates []time.Time
+		limit   int
+		want    []time.Time
+	}{
+		{
+			name:    "weekly by day with count",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=5",
+			dtstart: date(2026, time.January, 5, 9, 0), // Monday
+			want: []time.Time{
+				date(2026, time.January, 5, 9, 0),
+				date(2026, time.January, 7, 9, 0),
+				date(2026, time.January, 9, 9, 0),
+				date(2026, time.January, 12, 9, 0),
+				date(2026, time.January, 14, 9, 0),
+			},
+		},
+		{
+			name:    "weekly skips days before dtstart in the first week",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3",
+			dtstart: date(2026, time.January, 7, 18, 30), // Wednesday
+			want: []time.Time{
+				date(2026, time.January, 8, 18, 30),
+				date(2026, time.January, 12, 18, 30),
+				date(2026, time.January, 15, 18, 30),
+			},
+		},
+		{
+			name:    "every other week defaults to dtstart's weekday",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;COUNT=3",
+			dtstart: date(2026, time.January, 6, 7, 0), // Tuesday
+			want: []time.Time{
+				date(2026, time.January, 6, 7, 0),
+				date(2026, time.January, 20, 7, 0),
+				date(2026, time.February, 3, 7, 0),
+			},
+		},
+		{
+			name:    "daily until is inclusive",
+			rule:    "FREQ=DAILY;INTERVAL=3;UNTIL=20260107T140000Z",
+			dtstart: date(2026, time.January, 1, 9, 0),
+			want: []time.Time{
+				date(2026, time.January, 1, 9, 0),
+				date(2026, time.January, 4, 9, 0),
+				date(2026, time.January, 7, 9, 0),
+			},
+		},
+		{
+			name:    "daily by day keeps only those weekdays",
+			rule:    "FREQ=DAILY;BYDAY=SA,SU;COUNT=3",
+			dtstart: date(2026, time.January, 1, 10, 0), // Thursday
+			want: []time.Time{
+				date(2026, time.January, 3, 10, 0),
+				date(2026, time.January, 4, 10, 0),
+				date(2026, time.January, 10, 10, 0),
+			},
+		},
+		{
+			name:    "exdates count towards count",
+			rule:    "FREQ=DAILY;COUNT=3",
+			dtstart: date(2026, time.January, 1, 9, 0),
+			exdates: []time.Time{date(2026, time.January, 2, 9, 0)},
+			want: []time.Time{
+				date(2026, time.January, 1, 9, 0),
+				date(2026, time.January, 3, 9, 0),
+			},
+		},
+		{
+			name:    "limit stops expansion",
+			rule:    "FREQ=DAILY",
+			dtstart: date(2026, time.January, 1, 9, 0),
+			limit:   2,
+			want: []time.Time{
+				date(2026, time.January, 1, 9, 0),
+				date(2026, time.January, 2, 9, 0),
+			},
+		},
+		{
+			name:    "wall-clock time is kept across daylight saving start",
+			rule:    "FREQ=WEEKLY;BYDAY=SA;COUNT=2",
+			dtstart: date(2026, time.March, 7, 9, 0), // clocks go forward on March 8
+			want: []time.Time{
+				date(2026, time.March, 7, 9, 0),
+				date(2026, time.March, 14, 9, 0),
+			},
+		},
+		{
+			name:    "wall-clock time is kept across daylight saving end",
+			rule:    "FREQ=DAILY;COUNT=2",
+			dtstart: date(2026, time.October, 31, 18, 0), // clocks go back on November 1
+			want: []time.Time{
+				date(2026, time.October, 31, 18, 0),
+				date(2026, time.November, 1, 18, 0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule, tt.dtstart.Location())
+			if err != nil {
+				t.Fatalf("ParseRRule(%q) failed: %v", tt.rule, err)
+			}
+			got := rule.Expand(tt.dtstart, tt.exdates, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRRuleExpandDSTOffsets(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	rule, err := ParseRRule("FREQ=DAILY;COUNT=3", newYork)
+	if err != nil {
+		t.Fatalf("ParseRRule failed: %v", err)
+	}
+	got := rule.Expand(time.Date(2026, time.March, 7, 9, 0, 0, 0, newYork), nil, 0)
+
+	// 09:00 local is 14:00 UTC before the change and 13:00 UTC after it
+	wantUTC := []int{14, 13, 13}
+	for i, occurrence := range got {
+		if hour := occurrence.Hour(); hour != 9 {
+			t.Errorf("occurrence %d is at %d:00 local, want 9:00", i, hour)
+		}
+		if hour := occurrence.UTC().Hour(); hour != wantUTC[i] {
+			t.Errorf("occurrence %d is at %d:00 UTC, want %d:00", i, hour, wantUTC[i])
+		}
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=36", want: "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=36"},
+		{value: "RRULE:freq=daily;interval=2;until=20260301T090000Z", want: "FREQ=DAILY;INTERVAL=2;UNTIL=20260301T090000Z"},
+		{value: "FREQ=WEEKLY;INTERVAL=1;WKST=MO", want: "FREQ=WEEKLY"},
+		{value: "", wantErr: true},
+		{value: "BYDAY=MO", wantErr: true},
+		{value: "FREQ=MONTHLY", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{value: "FREQ=WEEKLY;INTERVAL=0", wantErr: true},
+		{value: "FREQ=WEEKLY;WKST=SU", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=5;UNTIL=20260301T090000Z", wantErr: true},
+		{value: "FREQ=DAILY;BYMONTH=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := ParseRRule(tt.value, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRRule() = %s, want an error", rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRRule() failed: %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRRuleUntilLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		until string
+		want  string
+	}{
+		{"UTC", "20260310T090000Z", "20260310T090000Z"},
+		{"offset", "2026-03-10T09:00:00+03:00", "20260310T060000Z"},
+		{"floating", "20260310T090000", "20260310T130000Z"},
+		{"date only", "20260310", "20260311T035959Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRRule("FREQ=DAILY;UNTIL="+tt.until, newYork)
+			if err != nil {
+				t.Fatalf("ParseRRule() failed: %v", err)
+			}
+			if got := rule.String(); got != "FREQ=DAILY;UNTIL="+tt.want {
+				t.Errorf("String() = %q, want UNTIL=%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClientRetention computes retention and adherence metrics for each of a trainer's
+// clients from their sessions since from, plus their package history, and flags
+// the clients that cross the thresholds.
+func ClientRetention(db *gorm.DB, trainerID uuid.UUID, from, now time.Time, thresholds models.RetentionThresholds) ([]models.ClientMetrics, error) {
+	var clients []models.Client
+	if err := db.Select("id", "first_name", "last_name", "created_at").
+		Where("trainer_id = ?", trainerID).
+		Order("first_name ASC, last_name ASC").
+		Find(&clients).Error; err != nil {
+		return nil, err
+	}
+
+	metrics := make([]models.ClientMetrics, len(clients))
+	byID := make(map[uuid.UUID]*models.ClientMetrics, len(clients))
+	for i, client := range clients {
+		metrics[i] = models.ClientMetrics{
+			ClientID:  client.ID,
+			FirstName: client.FirstName,
+			LastName:  client.LastName,
+		}
+		byID[client.ID] = &metrics[i]
+	}
+	if len(clients) == 0 {
+		return metrics, nil
+	}
+
+	sessions := func() *gorm.DB {
+		return db.Model(&models.Session{}).
+			Joins("JOIN clients ON clients.id = sessions.client_id").
+			Where("clients.trainer_id = ? AND clients.deleted_at IS NULL", trainerID)
+	}
+
+	// Past sessions in the window by status
+	var counts []struct {
+		ClientID uuid.UUID
+		Status   models.SessionStatus
+		Count    int
+	}
+	if err := sessions().
+		Where("sessions.scheduled_at >= ? AND sessions.scheduled_at < ?", from, now).
+		Select("sessions.client_id, sessions.status, COUNT(*) as count").
+		Group("sessions.client_id, sessions.status").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range counts {
+		m := byID[row.ClientID]
+		if m == nil {
+			continue
+		}
+		switch row.Status {
+		case models.SessionStatusCompleted:
+			m.Completed = row.Count
+		case models.SessionStatusNoShow:
+			m.NoShow = row.Count
+		case models.SessionStatusLateCancelled:
+			m.LateCancelled = row.Count
+		case models.SessionStatusCancelled:
+			m.Cancelled = row.Count
+		}
+	}
+
+	// Last completed session, at any time
+	var last []struct {
+		ClientID        uuid.UUID
+		LastCompletedAt time.Time
+	}
+	if err := sessions().
+		Where("sessions.status = ? AND sessions.scheduled_at < ?", models.SessionStatusCompleted, now).
+		Select("sessions.client_id, MAX(sessions.scheduled_at) as last_completed_at").
+		Group("sessions.client_id").
+		Scan(&last).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range last {
+		if m := byID[row.ClientID]; m != nil {
+			lastCompletedAt := row.LastCompletedAt
+			m.LastCompletedAt = &lastCompletedAt
+		}
+	}
+
+	// Sessions booked ahead
+	var upcoming []struct {
+		ClientID uuid.UUID
+		Count    int
+	}
+	if err := sessions().
+		Where("sessions.status IN ? AND sessions.scheduled_at >= ?",
+			[]models.SessionStatus{models.SessionStatusPending, models.SessionStatusScheduled}, now).
+		Select("sessions.client_id, COUNT(*) as count").
+		Group("sessions.client_id").
+		Scan(&upcoming).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range upcoming {
+		if m := byID[row.ClientID]; m != nil {
+			m.UpcomingSessions = row.Count
+		}
+	}
+
+	if err := countRenewals(db, trainerID, now, byID); err != nil {
+		return nil, err
+	}
+
+	for i, client := range clients {
+		since := from
+		if client.CreatedAt.After(since) {
+			since = client.CreatedAt
+		}
+		metrics[i].Evaluate(thresholds, since, now)
+	}
+
+	return metrics, nil
+}
+
+// countRenewals counts, per client, the packages that have finished (used up or
+// expired) and how many of those were renewed, i.e. followed by a package bought
+// once they had finished. A package bought alongside one still running is not a renewal.
+func countRenewals(db *gorm.DB, trainerID uuid.UUID, now time.Time, byID map[uuid.UUID]*models.ClientMetrics) error {
+	var packages []struct {
+		ClientID     uuid.UUID
+		SessionCount int
+		PurchasedAt  time.Time
+		ExpiresAt    *time.Time
+		Used         int
+		LastUsedAt   *time.Time
+	}
+	if err := db.Model(&models.Package{}).
+		Joins("JOIN clients ON clients.id = packages.client_id").
+		Joins("LEFT JOIN sessions ON sessions.package_id = packages.id AND sessions.status IN ? AND sessions.deleted_at IS NULL",
+			models.UsedStatuses()).
+		Where("clients.trainer_id = ? AND clients.deleted_at IS NULL AND packages.status <> ?",
+			trainerID, models.PackageStatusCancelled).
+		Select(`packages.id, packages.client_id, packages.session_count, packages.purchased_at, packages.expires_at,
+			COUNT(sessions.id) as used, MAX(sessions.scheduled_at) as last_used_at`).
+		Group("packages.id").
+		Order("packages.client_id, packages.purchased_at ASC").
+		Scan(&packages).Error; err != nil {
+		return err
+	}
+
+	for i, pkg := range packages {
+		m := byID[pkg.ClientID]
+		if m == nil {
+			continue
+		}
+
+		// A package finishes with the session that used it up or when it expires, whichever is first
+		var finishedAt *time.Time
+		if pkg.Used >= pkg.SessionCount && pkg.LastUsedAt != nil {
+			finishedAt = pkg.LastUsedAt
+		}
+		if pkg.ExpiresAt != nil && !pkg.ExpiresAt.After(now) && (finishedAt == nil || pkg.ExpiresAt.Before(*finishedAt)) {
+			finishedAt = pkg.ExpiresAt
+		}
+		if finishedAt == nil {
+			continue
+		}
+		m.PackagesFinished++
+
+		// Packages are ordered by client and purchase date, so later purchases follow this row
+		for _, next := range packages[i+1:] {
+			if next.ClientID != pkg.ClientID {
+				break
+			}
+			if !next.PurchasedAt.Before(*finishedAt) {
+				m.PackagesRenewed++
+				break
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrainingStats computes a client's streaks and totals from their completed
+// sessions, with weeks counted in loc
+func TrainingStats(db *gorm.DB, clientID uuid.UUID, loc *time.Location, now time.Time) (models.ClientTrainingStats, error) {
+	var stats models.ClientTrainingStats
+
+	var totals struct {
+		Workouts int
+		Minutes  int
+		LastAt   *time.Time
+	}
+	if err := db.Model(&models.Session{}).
+		Where("client_id = ? AND status = ?", clientID, models.SessionStatusCompleted).
+		Select("COUNT(*) as workouts, COALESCE(SUM(duration_minutes), 0) as minutes, MAX(scheduled_at) as last_at").
+		Scan(&totals).Error; err != nil {
+		return stats, err
+	}
+	stats.TotalWorkouts = totals.Workouts
+	stats.MinutesTrained = totals.Minutes
+	if totals.LastAt != nil {
+		last := totals.LastAt.In(loc)
+		stats.LastSessionAt = &last
+	}
+
+	var next models.Session
+	err := db.Where("client_id = ? AND status = ? AND scheduled_at >= ?", clientID, models.SessionStatusScheduled, now).
+		Order("scheduled_at ASC").
+		Limit(1).
+		Find(&next).Error
+	if err != nil {
+		return stats, err
+	}
+	if next.ID != uuid.Nil {
+		nextAt := next.ScheduledAt.In(loc)
+		stats.NextSessionAt = &nextAt
+	}
+
+	// Postgres weeks start on Monday; the local week start is read back as a UTC date
+	var weeks []time.Time
+	if err := db.Model(&models.Session{}).
+		Where("client_id = ? AND status = ?", clientID, models.SessionStatusCompleted).
+		Select("DISTINCT date_trunc('week', scheduled_at AT TIME ZONE ?) as week", loc.String()).
+		Order("week ASC").
+		Scan(&weeks).Error; err != nil {
+		return stats, err
+	}
+
+	stats.CurrentStreakWeeks, stats.LongestStreakWeeks = WeeklyStreaks(weeks, weekStart(now.In(loc)))
+	return stats, nil
+}
+
+// WeeklyStreaks returns the current and longest runs of consecutive weeks in
+// weeks (ascending week start dates). The current streak is still alive if its
+// last week is thisWeek or the week before, since this week may not be over.
+func WeeklyStreaks(weeks []time.Time, thisWeek time.Time) (current, longest int) {
+	run := 0
+	var previous time.Time
+	for i, week := range weeks {
+		if i > 0 && DaysBetween(previous, week) == 7 {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		previous = week
+	}
+
+	if len(weeks) > 0 {
+		if gap := DaysBetween(previous, thisWeek); gap == 0 || gap == 7 {
+			current = run
+		}
+	}
+	return current, longest
+}
+
+// weekStart returns the Monday of t's week as a UTC date
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	monday := t.AddDate(0, 0, -offset)
+	return time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DaysBetween returns the number of whole days from a to b, ignoring the time of day
+func DaysBetween(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
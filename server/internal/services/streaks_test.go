@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeklyStreaks(t *testing.T) {
+	// Mondays from January 5, 2026
+	week := func(n int) time.Time {
+		return time.Date(2026, time.January, 5+7*n, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		weeks       []time.Time
+		thisWeek    time.Time
+		wantCurrent int
+		wantLongest int
+	}{
+		{
+			name:     "no weeks",
+			thisWeek: week(3),
+		},
+		{
+			name:        "run ending this week",
+			weeks:       []time.Time{week(0), week(1), week(2), week(3)},
+			thisWeek:    week(3),
+			wantCurrent: 4,
+			wantLongest: 4,
+		},
+		{
+			name:        "run ending last week is still alive",
+			weeks:       []time.Time{week(1), week(2)},
+			thisWeek:    week(3),
+			wantCurrent: 2,
+			wantLongest: 2,
+		},
+		{
+			name:        "run ending two weeks ago is broken",
+			weeks:       []time.Time{week(0), week(1)},
+			thisWeek:    week(3),
+			wantCurrent: 0,
+			wantLongest: 2,
+		},
+		{
+			name:        "gap restarts the run",
+			weeks:       []time.Time{week(0), week(1), week(2), week(4), week(5)},
+			thisWeek:    week(5),
+			wantCurrent: 2,
+			wantLongest: 3,
+		},
+		{
+			name:        "across a year boundary",
+			weeks:       []time.Time{week(-2), week(-1), week(0)},
+			thisWeek:    week(0),
+			wantCurrent: 3,
+			wantLongest: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := WeeklyStreaks(tt.weeks, tt.thisWeek)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("WeeklyStreaks() = %d, %d, want %d, %d", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
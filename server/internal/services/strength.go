@@ -0,0 +1,80 @@
+package services
+
+import (
+	"math"
+	"strings"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecalculatePersonalBests walks a client's records of a lift in test order and
+// flags each one that beats every earlier estimated one-rep max.
+// Lift names are compared case-insensitively.
+func RecalculatePersonalBests(tx *gorm.DB, clientID uuid.UUID, lift string) error {
+	var records []models.StrengthRecord
+	if err := tx.Where("client_id = ? AND LOWER(lift) = ?", clientID, strings.ToLower(lift)).
+		Order("tested_at ASC, created_at ASC").
+		Find(&records).Error; err != nil {
+		return err
+	}
+
+	best := 0.0
+	for _, record := range records {
+		isBest := record.EstimatedOneRM > best
+		if isBest {
+			best = record.EstimatedOneRM
+		}
+		if record.IsPersonalBest != isBest {
+			if err := tx.Model(&record).UpdateColumn("is_personal_best", isBest).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// StrengthProgression groups a client's records by lift into time series,
+// optionally only for one lift
+func StrengthProgression(db *gorm.DB, clientID uuid.UUID, lift string) ([]models.StrengthProgress, error) {
+	query := db.Where("client_id = ?", clientID)
+	if lift != "" {
+		query = query.Where("LOWER(lift) = ?", strings.ToLower(lift))
+	}
+
+	var records []models.StrengthRecord
+	if err := query.Order("tested_at ASC, created_at ASC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	progress := make([]models.StrengthProgress, 0)
+	index := map[string]int{}
+	for _, record := range records {
+		key := strings.ToLower(record.Lift)
+		i, ok := index[key]
+		if !ok {
+			i = len(progress)
+			index[key] = i
+			progress = append(progress, models.StrengthProgress{Lift: record.Lift})
+		}
+
+		p := &progress[i]
+		p.Points = append(p.Points, models.StrengthPoint{
+			RecordID:       record.ID,
+			TestedAt:       record.TestedAt,
+			LoadKg:         record.LoadKg,
+			Reps:           record.Reps,
+			EstimatedOneRM: record.EstimatedOneRM,
+			IsPersonalBest: record.IsPersonalBest,
+		})
+		if record.EstimatedOneRM > p.BestOneRM {
+			p.BestOneRM = record.EstimatedOneRM
+		}
+		p.LatestOneRM = record.EstimatedOneRM
+		p.ChangeOneRM = math.Round((p.LatestOneRM-p.Points[0].EstimatedOneRM)*100) / 100
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Days of load behind the acute and chronic workloads
+const (
+	acuteDays   = 7
+	chronicDays = 28
+)
+
+// dayLoad is the summed load of the sessions on one day
+type dayLoad struct {
+	Sessions int
+	Load     int
+}
+
+// DailyTrainingLoad returns the client's session-RPE load for each of the last
+// days days up to today in loc, with the rolling acute:chronic workload ratio
+func DailyTrainingLoad(db *gorm.DB, clientID uuid.UUID, loc *time.Location, days int, now time.Time) ([]models.DailyLoad, error) {
+	today := localDate(now.In(loc))
+	return dailyTrainingLoad(db, clientID, loc, today.AddDate(0, 0, -(days-1)), today)
+}
+
+// WeeklyTrainingLoad returns the client's session-RPE load for each of the last
+// weeks weeks in loc, including the current one up to today
+func WeeklyTrainingLoad(db *gorm.DB, clientID uuid.UUID, loc *time.Location, weeks int, now time.Time) ([]models.WeeklyLoad, error) {
+	today := localDate(now.In(loc))
+	first := weekStart(today).AddDate(0, 0, -7*(weeks-1))
+
+	days, err := dailyTrainingLoad(db, clientID, loc, first, today)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.WeeklyLoad, 0, weeks)
+	for i := 0; i < len(days); i += 7 {
+		week := days[i:min(i+7, len(days))]
+
+		load := models.WeeklyLoad{WeekStart: week[0].Date}
+		daily := make([]int, len(week))
+		for j, day := range week {
+			load.Sessions += day.Sessions
+			load.Load += day.Load
+			daily[j] = day.Load
+		}
+		load.Monotony = models.Monotony(daily)
+		if load.Monotony != nil {
+			strain := math.Round(float64(load.Load)**load.Monotony*100) / 100
+			load.Strain = &strain
+		}
+
+		last := week[len(week)-1]
+		load.ACWR = last.ACWR
+		load.ACWRWarning = last.ACWRWarning
+		result = append(result, load)
+	}
+
+	return result, nil
+}
+
+// dailyTrainingLoad builds the daily series from first to last (local dates),
+// reading 27 more days before first for the chronic load
+func dailyTrainingLoad(db *gorm.DB, clientID uuid.UUID, loc *time.Location, first, last time.Time) ([]models.DailyLoad, error) {
+	start := first.AddDate(0, 0, -(chronicDays - 1))
+	loads, err := loadsByDay(db, clientID, loc, start, last)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.DailyLoad, 0, DaysBetween(first, last)+1)
+	window := make([]int, 0, chronicDays+1)
+	for day := start; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		window = append(window, loads[date].Load)
+		if len(window) > chronicDays {
+			window = window[1:]
+		}
+		if day.Before(first) {
+			continue
+		}
+
+		acute := sum(window[len(window)-acuteDays:])
+		chronic := float64(sum(window)) / (chronicDays / acuteDays)
+		acwr := models.ACWR(acute, chronic)
+		result = append(result, models.DailyLoad{
+			Date:        date,
+			Sessions:    loads[date].Sessions,
+			Load:        loads[date].Load,
+			AcuteLoad:   acute,
+			ChronicLoad: math.Round(chronic*100) / 100,
+			ACWR:        acwr,
+			ACWRWarning: models.IsACWRWarning(acwr),
+		})
+	}
+
+	return result, nil
+}
+
+// loadsByDay sums the load of the client's completed sessions with a recorded
+// RPE for each local date from first to last, keyed by YYYY-MM-DD
+func loadsByDay(db *gorm.DB, clientID uuid.UUID, loc *time.Location, first, last time.Time) (map[string]dayLoad, error) {
+	from := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, loc)
+
+	var rows []struct {
+		Day      time.Time
+		Sessions int
+		Load     int
+	}
+	if err := db.Model(&models.Session{}).
+		Select("(scheduled_at AT TIME ZONE ?)::date as day, COUNT(*) as sessions, SUM(rpe * duration_minutes) as load", loc.String()).
+		Where("client_id = ? AND status = ? AND rpe IS NOT NULL", clientID, models.SessionStatusCompleted).
+		Where("scheduled_at >= ? AND scheduled_at < ?", from, to).
+		Group("day").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	loads := make(map[string]dayLoad, len(rows))
+	for _, row := range rows {
+		loads[row.Day.Format("2006-01-02")] = dayLoad{Sessions: row.Sessions, Load: row.Load}
+	}
+	return loads, nil
+}
+
+// localDate returns t's calendar date as a UTC date
+func localDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// sum adds up values
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ptmate/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SafetyCheck returns the client's vitals on the day of at, in loc, with their
+// flags, and the warnings for training that day: one for each flagged reading, and
+// one if no vitals were taken while the client's latest PAR-Q reports heart symptoms.
+func SafetyCheck(db *gorm.DB, clientID uuid.UUID, at time.Time, loc *time.Location) ([]models.Vitals, []string, error) {
+	local := at.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var vitals []models.Vitals
+	if err := db.Where("client_id = ? AND measured_at >= ? AND measured_at < ?", clientID, start, start.AddDate(0, 0, 1)).
+		Order("measured_at ASC").
+		Find(&vitals).Error; err != nil {
+		return nil, nil, err
+	}
+
+	warnings := []string{}
+	for i := range vitals {
+		vitals[i].SetFlags()
+		for _, flag := range vitals[i].Flags {
+			warnings = append(warnings, fmt.Sprintf("Unsafe vitals at %s: %s", vitals[i].MeasuredAt.In(loc).Format("15:04"), flag))
+		}
+	}
+
+	if len(vitals) == 0 {
+		var assessment models.Assessment
+		err := db.Where("client_id = ?", clientID).Order("created_at DESC").First(&assessment).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, err
+		}
+		if err == nil && assessment.HasCardiacFlags() {
+			warnings = append(warnings, fmt.Sprintf("No vitals recorded on %s, and the client's PAR-Q reports heart symptoms", start.Format("2006-01-02")))
+		}
+	}
+
+	return vitals, warnings, nil
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	_ "time/tzdata" // embed timezone data for trainer timezones
 
 	"ptmate/internal/config"
 	"ptmate/internal/database"
@@ -24,21 +26,69 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	// Global exercises must be unique by key before the index on it is created
+	if err := database.DedupeExercises(db); err != nil {
+		log.Fatalf("Failed to deduplicate exercises: %v", err)
+	}
+
 	// Auto migrate database schemas
 	if err := db.AutoMigrate(
 		&models.Trainer{},
 		&models.Client{},
+		&models.Package{},
+		&models.PackageFreeze{},
+		&models.PackageInstallment{},
+		&models.Payment{},
+		&models.Invoice{},
+		&models.InvoiceDocument{},
+		&models.SessionSeries{},
 		&models.Session{},
+		&models.SessionStatusEvent{},
+		&models.Exercise{},
+		&models.Program{},
+		&models.ProgramWeek{},
+		&models.ProgramDay{},
+		&models.ProgramExercise{},
+		&models.WorkoutLog{},
+		&models.WorkoutExercise{},
+		&models.WorkoutSet{},
 		&models.Measurement{},
+		&models.CustomMetric{},
+		&models.CustomMetricValue{},
+		&models.Skinfold{},
+		&models.Bioimpedance{},
+		&models.StrengthRecord{},
+		&models.Goal{},
 		&models.Assessment{},
+		&models.Vitals{},
 		&models.PhotoGroup{},
 		&models.Photo{},
+		&models.WorkingHours{},
+		&models.AvailabilityOverride{},
+		&models.TimeOff{},
 	); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	if err := database.MigratePackages(db); err != nil {
+		log.Fatalf("Failed to migrate packages: %v", err)
+	}
+
+	if err := database.SeedExercises(db); err != nil {
+		log.Fatalf("Failed to seed exercises: %v", err)
+	}
+
 	log.Println("Database migration completed successfully")
 
+	// Resolve sessions whose attendance was never recorded
+	services.NewNoShowWorker(db, cfg.NoShowGracePeriod, cfg.NoShowCheckInterval).Start(context.Background())
+
+	// Give packages sold ahead of their start the sessions no package covered
+	services.NewPackageStartJob(db, cfg.PackageCheckInterval).Start(context.Background())
+
+	// Move goals to missed once their deadline has passed
+	services.NewGoalDeadlineJob(db, cfg.GoalCheckInterval).Start(context.Background())
+
 	// Setup Gin router
 	router := gin.Default()
 
@@ -68,6 +118,15 @@ func main() {
 			auth.POST("/login", authHandler.Login)
 		}
 
+		// Public self-booking routes (scoped by the trainer's booking token)
+		bookingHandler := handlers.NewBookingHandler(db)
+		booking := api.Group("/public/booking/:token")
+		{
+			booking.GET("", bookingHandler.GetProfile)
+			booking.GET("/slots", bookingHandler.GetSlots)
+			booking.POST("/requests", bookingHandler.CreateRequest)
+		}
+
 		// Protected routes (require authentication)
 		protected := api.Group("")
 		protected.Use(middleware.Auth())
@@ -77,6 +136,12 @@ func main() {
 			// Accept terms
 			protected.POST("/auth/terms", authHandler.AcceptTerms)
 
+			// Trainer settings routes
+			settingsHandler := handlers.NewSettingsHandler(db)
+			protected.GET("/settings", settingsHandler.Get)
+			protected.PUT("/settings", settingsHandler.Update)
+			protected.POST("/settings/booking-token", settingsHandler.RegenerateBookingToken)
+
 			// Client routes
 			clientHandler := handlers.NewClientHandler(db)
 			clients := protected.Group("/clients")
@@ -84,10 +149,44 @@ func main() {
 				clients.GET("", clientHandler.GetAll)
 				clients.POST("", clientHandler.Create)
 				clients.GET("/:id", clientHandler.GetByID)
+				clients.GET("/:id/stats", clientHandler.GetStats)
 				clients.PUT("/:id", clientHandler.Update)
 				clients.DELETE("/:id", clientHandler.Delete)
 				clients.GET("/:id/measurements", clientHandler.GetMeasurements)
 				clients.POST("/:id/measurements", clientHandler.CreateMeasurement)
+				clients.GET("/:id/measurements/progress", clientHandler.GetMeasurementProgress)
+			}
+
+			// Package routes
+			packageHandler := handlers.NewPackageHandler(db)
+			clients.GET("/:id/packages", packageHandler.GetByClient)
+			clients.POST("/:id/packages", packageHandler.Create)
+			packages := protected.Group("/packages")
+			{
+				packages.GET("/:id", packageHandler.GetByID)
+				packages.PUT("/:id", packageHandler.Update)
+				packages.POST("/:id/freeze", packageHandler.Freeze)
+				packages.POST("/:id/unfreeze", packageHandler.Unfreeze)
+				packages.GET("/:id/installments", packageHandler.GetInstallments)
+				packages.PUT("/:id/installments", packageHandler.SetInstallments)
+			}
+
+			// Payment and balance routes
+			paymentHandler := handlers.NewPaymentHandler(db)
+			clients.GET("/:id/payments", paymentHandler.GetByClient)
+			clients.POST("/:id/payments", paymentHandler.Create)
+			clients.GET("/:id/balance", paymentHandler.GetBalance)
+			protected.DELETE("/payments/:id", paymentHandler.Delete)
+			protected.GET("/finances/outstanding", paymentHandler.GetOutstanding)
+
+			// Invoice routes
+			invoiceHandler := handlers.NewInvoiceHandler(db)
+			packages.POST("/:id/invoices", invoiceHandler.Create)
+			clients.GET("/:id/invoices", invoiceHandler.GetByClient)
+			invoices := protected.Group("/invoices")
+			{
+				invoices.GET("/:id", invoiceHandler.GetByID)
+				invoices.GET("/:id/pdf", invoiceHandler.DownloadPDF)
 			}
 
 			// Session routes
@@ -96,12 +195,57 @@ func main() {
 			{
 				sessions.GET("", sessionHandler.GetAll)
 				sessions.POST("", sessionHandler.Create)
+				sessions.POST("/check-conflicts", sessionHandler.CheckConflicts)
 				sessions.GET("/:id", sessionHandler.GetByID)
 				sessions.PUT("/:id", sessionHandler.Update)
 				sessions.PATCH("/:id/status", sessionHandler.UpdateStatus)
+				sessions.GET("/:id/timeline", sessionHandler.GetTimeline)
+				sessions.POST("/:id/approve", sessionHandler.Approve)
+				sessions.POST("/:id/reject", sessionHandler.Reject)
 				sessions.DELETE("/:id", sessionHandler.Delete)
 			}
 
+			// Exercise library routes
+			exerciseHandler := handlers.NewExerciseHandler(db)
+			exercises := protected.Group("/exercises")
+			{
+				exercises.GET("", exerciseHandler.GetAll)
+				exercises.GET("/options", exerciseHandler.GetOptions)
+				exercises.POST("", exerciseHandler.Create)
+				exercises.GET("/:id", exerciseHandler.GetByID)
+				exercises.PUT("/:id", exerciseHandler.Update)
+				exercises.DELETE("/:id", exerciseHandler.Delete)
+			}
+
+			// Training program routes
+			programHandler := handlers.NewProgramHandler(db)
+			clients.GET("/:id/programs", programHandler.GetByClient)
+			programs := protected.Group("/programs")
+			{
+				programs.GET("", programHandler.GetAll)
+				programs.POST("", programHandler.Create)
+				programs.GET("/:id", programHandler.GetByID)
+				programs.PUT("/:id", programHandler.Update)
+				programs.DELETE("/:id", programHandler.Delete)
+				programs.POST("/:id/assign", programHandler.Assign)
+			}
+
+			// Workout log routes
+			workoutHandler := handlers.NewWorkoutHandler(db)
+			sessions.GET("/:id/workout", workoutHandler.Get)
+			sessions.PUT("/:id/workout", workoutHandler.Save)
+			sessions.DELETE("/:id/workout", workoutHandler.Delete)
+
+			// Recurring session series routes
+			seriesHandler := handlers.NewSessionSeriesHandler(db)
+			series := protected.Group("/session-series")
+			{
+				series.GET("", seriesHandler.GetAll)
+				series.POST("", seriesHandler.Create)
+				series.GET("/:id", seriesHandler.GetByID)
+				series.DELETE("/:id", seriesHandler.Delete)
+			}
+
 			// Measurement routes
 			measurementHandler := handlers.NewMeasurementHandler(db)
 			measurements := protected.Group("/measurements")
@@ -111,10 +255,96 @@ func main() {
 				measurements.DELETE("/:id", measurementHandler.Delete)
 			}
 
+			// Custom measurement metric routes
+			customMetricHandler := handlers.NewCustomMetricHandler(db)
+			customMetrics := protected.Group("/custom-metrics")
+			{
+				customMetrics.GET("", customMetricHandler.GetAll)
+				customMetrics.POST("", customMetricHandler.Create)
+				customMetrics.PUT("/:id", customMetricHandler.Update)
+				customMetrics.DELETE("/:id", customMetricHandler.Delete)
+			}
+
+			// Skinfold routes
+			skinfoldHandler := handlers.NewSkinfoldHandler(db)
+			clients.GET("/:id/skinfolds", skinfoldHandler.GetByClient)
+			clients.POST("/:id/skinfolds", skinfoldHandler.Create)
+			skinfolds := protected.Group("/skinfolds")
+			{
+				skinfolds.GET("/:id", skinfoldHandler.GetByID)
+				skinfolds.PUT("/:id", skinfoldHandler.Update)
+				skinfolds.DELETE("/:id", skinfoldHandler.Delete)
+			}
+
+			// Bioimpedance routes
+			bioimpedanceHandler := handlers.NewBioimpedanceHandler(db)
+			clients.GET("/:id/bioimpedance", bioimpedanceHandler.GetByClient)
+			clients.POST("/:id/bioimpedance", bioimpedanceHandler.Create)
+			bioimpedance := protected.Group("/bioimpedance")
+			{
+				bioimpedance.GET("/:id", bioimpedanceHandler.GetByID)
+				bioimpedance.PUT("/:id", bioimpedanceHandler.Update)
+				bioimpedance.DELETE("/:id", bioimpedanceHandler.Delete)
+			}
+
+			// Training load routes
+			trainingLoadHandler := handlers.NewTrainingLoadHandler(db)
+			clients.GET("/:id/training-load/daily", trainingLoadHandler.GetDaily)
+			clients.GET("/:id/training-load/weekly", trainingLoadHandler.GetWeekly)
+
+			// Strength test routes
+			strengthHandler := handlers.NewStrengthRecordHandler(db)
+			clients.GET("/:id/strength-records", strengthHandler.GetByClient)
+			clients.POST("/:id/strength-records", strengthHandler.Create)
+			clients.GET("/:id/strength-records/progress", strengthHandler.GetProgress)
+			strengthRecords := protected.Group("/strength-records")
+			{
+				strengthRecords.PUT("/:id", strengthHandler.Update)
+				strengthRecords.DELETE("/:id", strengthHandler.Delete)
+			}
+
+			// Goal routes
+			goalHandler := handlers.NewGoalHandler(db)
+			clients.GET("/:id/goals", goalHandler.GetByClient)
+			clients.POST("/:id/goals", goalHandler.Create)
+			goals := protected.Group("/goals")
+			{
+				goals.GET("/:id", goalHandler.GetByID)
+				goals.PUT("/:id", goalHandler.Update)
+				goals.DELETE("/:id", goalHandler.Delete)
+			}
+
 			// Dashboard routes
 			dashboardHandler := handlers.NewDashboardHandler(db)
 			protected.GET("/dashboard", dashboardHandler.GetDashboard)
 			protected.GET("/calendar", dashboardHandler.GetCalendar)
+			protected.GET("/analytics", dashboardHandler.GetAnalytics)
+
+			// Report routes
+			reportHandler := handlers.NewReportHandler(db)
+			reports := protected.Group("/reports")
+			{
+				reports.GET("/revenue", reportHandler.GetRevenue)
+				reports.GET("/revenue-by-client", reportHandler.GetRevenueByClient)
+				reports.GET("/session-value", reportHandler.GetSessionValue)
+				reports.GET("/expected-revenue", reportHandler.GetExpectedRevenue)
+				reports.GET("/unearned-revenue", reportHandler.GetUnearnedRevenue)
+			}
+
+			// Availability routes
+			availabilityHandler := handlers.NewAvailabilityHandler(db)
+			availability := protected.Group("/availability")
+			{
+				availability.GET("/working-hours", availabilityHandler.GetWorkingHours)
+				availability.PUT("/working-hours", availabilityHandler.SetWorkingHours)
+				availability.GET("/overrides", availabilityHandler.GetOverrides)
+				availability.POST("/overrides", availabilityHandler.CreateOverride)
+				availability.DELETE("/overrides/:id", availabilityHandler.DeleteOverride)
+				availability.GET("/time-off", availabilityHandler.GetTimeOff)
+				availability.POST("/time-off", availabilityHandler.CreateTimeOff)
+				availability.DELETE("/time-off/:id", availabilityHandler.DeleteTimeOff)
+				availability.GET("/slots", availabilityHandler.GetSlots)
+			}
 
 			// Assessment routes
 			assessmentHandler := handlers.NewAssessmentHandler(db)
@@ -129,6 +359,18 @@ func main() {
 				assessments.DELETE("/:id", assessmentHandler.Delete)
 			}
 
+			// Vitals routes
+			vitalsHandler := handlers.NewVitalsHandler(db)
+			clients.GET("/:id/vitals", vitalsHandler.GetByClient)
+			clients.POST("/:id/vitals", vitalsHandler.Create)
+			sessions.GET("/:id/safety-check", vitalsHandler.SafetyCheck)
+			vitals := protected.Group("/vitals")
+			{
+				vitals.GET("/:id", vitalsHandler.GetByID)
+				vitals.PUT("/:id", vitalsHandler.Update)
+				vitals.DELETE("/:id", vitalsHandler.Delete)
+			}
+
 			// Photo routes
 			var r2Service *services.R2Service
 			if cfg.R2AccountID != "" && cfg.R2AccessKey != "" {